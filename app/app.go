@@ -9,6 +9,8 @@ import (
 	"proyek1/internal/usecase"
 	"proyek1/utils/gmaps"
 	"proyek1/utils/mailer"
+	"proyek1/utils/places"
+	"proyek1/utils/routing"
 
 	"proyek1/utils"
 
@@ -17,32 +19,42 @@ import (
 )
 
 type BootstrapConfig struct {
-	DB   *sql.DB
-	App  *gin.Engine
-	Log  *logrus.Logger
-	JWT  utils.JWTInterface
-	Cfg  *config.Config
-	M    mailer.MailInterface
-	Maps gmaps.GmapsInterface
+	DB     *sql.DB
+	App    *gin.Engine
+	Log    *logrus.Logger
+	JWT    utils.JWTInterface
+	Cfg    *config.Config
+	M      mailer.MailInterface
+	Maps   gmaps.GmapsInterface
+	Places places.Provider
+	Route  routing.RoutingProvider
+	Photo  utils.PhotoSignerInterface
 }
 
 func App(config *BootstrapConfig) {
 	// Repository
 	userRepository := repository.NewUserRepository(config.DB, config.Log)
 	mapsRepository := repository.NewMapsRepository(config.DB, config.Log)
+	usageRepository := repository.NewGmapsUsageRepository(config.DB, config.Log)
+	sessionRepository := repository.NewSessionRepository(config.DB, config.Log)
 
 	// UseCase
-	userUsecase := usecase.NewUserUsecase(config.JWT, userRepository, config.Log, config.Cfg, config.M)
-	mapsUsecase := usecase.NewMapsUsercase(mapsRepository, config.Log, config.Maps)
+	userUsecase := usecase.NewUserUsecase(config.JWT, userRepository, sessionRepository, config.Log, config.Cfg, config.M)
+	mapsUsecase := usecase.NewMapsUsercase(mapsRepository, config.Log, config.Places, config.Route, config.Photo)
+	usageUsecase := usecase.NewGmapsUsageUsecase(usageRepository, config.Cfg.GmapsQuota, config.Log)
 	// Delivery
 	userHandler := delivery.NewUserHandler(config.JWT, userUsecase, config.Log)
-	mapsHandler := delivery.NewMapsHandler(config.JWT, config.Maps, mapsUsecase)
+	mapsHandler := delivery.NewMapsHandler(config.JWT, config.Maps, config.Places, config.Photo, mapsUsecase)
+	usageHandler := delivery.NewGmapsUsageHandler(usageUsecase, config.Log)
 
 	routeConfig := routes.RouteConfig{
-		App:            config.App,
-		UserController: userHandler,
-		MapsController: &mapsHandler,
-		JWT:            config.JWT,
+		App:             config.App,
+		UserController:  userHandler,
+		MapsController:  &mapsHandler,
+		UsageController: usageHandler,
+		JWT:             config.JWT,
+		Sessions:        userUsecase,
+		Cfg:             config.Cfg,
 	}
 
 	routeConfig.Setup()
@@ -3,7 +3,10 @@ package config
 import (
 	"log"
 	"os"
+	"proyek1/constant"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -17,6 +20,13 @@ type Config struct {
 	GeneralPhoto General
 	SMTP         SMTP
 	Gmaps        GMAPS
+	GmapsCache   GMAPS_CACHE
+	GmapsQuota   GMAPS_QUOTA
+	Photo        PHOTO
+	Routing      ROUTING
+	Places       PLACES
+	Auth         AUTH
+	Server       SERVER
 	URL_Server   string
 }
 
@@ -42,7 +52,62 @@ type SMTP struct {
 }
 
 type GMAPS struct {
-	GMAPS_API_KEY string
+	GMAPS_API_KEY     string
+	GMAPS_MODE        string // live, fixture atau record
+	GMAPS_FIXTURE_DIR string
+	GMAPS_TIMEOUT     time.Duration // per request, default 10s
+	GMAPS_MAX_RETRIES int           // retry untuk 5xx/429/OVER_QUERY_LIMIT, default 2
+}
+
+// TTL 0 pakai default, TTL negatif berarti cache untuk jenis itu dimatikan
+type GMAPS_CACHE struct {
+	GMAPS_CACHE_SIZE       int
+	GMAPS_TTL_SEARCH       time.Duration
+	GMAPS_TTL_DETAILS      time.Duration
+	GMAPS_TTL_GEOCODE      time.Duration
+	GMAPS_TTL_ROUTE        time.Duration
+	GMAPS_TTL_AUTOCOMPLETE time.Duration
+}
+
+// Key map = jenis panggilan (search, list, details, photo, route, geocode, reverse, autocomplete).
+// Budget 0 / tidak diisi berarti tanpa batas, harga dalam USD per 1000 panggilan
+type GMAPS_QUOTA struct {
+	GMAPS_BUDGET_DAILY   map[string]int
+	GMAPS_BUDGET_MONTHLY map[string]int
+	GMAPS_BUDGET_WARN    int // persen dari budget, lewat dari ini mulai di-log
+	GMAPS_PRICES         map[string]float64
+}
+
+type PHOTO struct {
+	PHOTO_CACHE_DIR    string
+	PHOTO_CACHE_MAX_MB int
+	PHOTO_URL_SECRET   string
+	PHOTO_URL_TTL      time.Duration
+	PHOTO_RATE_LIMIT   int // request per menit per IP
+	PHOTO_RATE_BURST   int
+}
+
+type PLACES struct {
+	PLACES_PROVIDER string // google atau osm
+	NOMINATIM_URL   string
+	NOMINATIM_EMAIL string
+}
+
+// TTL 0 pakai default (constant.AccessTokenTTL dan RefreshTokenTTL)
+type AUTH struct {
+	ACCESS_TOKEN_TTL  time.Duration
+	REFRESH_TOKEN_TTL time.Duration // sesi habis kalau tidak di-refresh selama ini
+}
+
+// Kosong = tidak ada reverse proxy, X-Forwarded-For diabaikan dan IP client diambil dari koneksi.
+// Isi IP/CIDR proxy (contoh nginx di 127.0.0.1) supaya ClientIP membaca X-Forwarded-For dari proxy itu saja
+type SERVER struct {
+	TRUSTED_PROXIES []string
+}
+
+type ROUTING struct {
+	ROUTING_PROVIDER string // urutan fallback, contoh "google,osrm,straight"
+	OSRM_URL         string
 }
 
 func EnvFile() *Config {
@@ -52,6 +117,15 @@ func EnvFile() *Config {
 	}
 	port, _ := strconv.Atoi(os.Getenv("DATABASE_PORT"))
 	portSMTP, _ := strconv.Atoi(os.Getenv("SMTP_PORT"))
+	cacheSize, _ := strconv.Atoi(os.Getenv("GMAPS_CACHE_SIZE"))
+	photoCacheMB, _ := strconv.Atoi(os.Getenv("PHOTO_CACHE_MAX_MB"))
+	photoRate, _ := strconv.Atoi(os.Getenv("PHOTO_RATE_LIMIT"))
+	photoBurst, _ := strconv.Atoi(os.Getenv("PHOTO_RATE_BURST"))
+	budgetWarn, _ := strconv.Atoi(os.Getenv("GMAPS_BUDGET_WARN"))
+	gmapsRetries, err := strconv.Atoi(os.Getenv("GMAPS_MAX_RETRIES"))
+	if err != nil {
+		gmapsRetries = constant.GmapsRetries
+	}
 	return &Config{
 		Database: Database{
 			dbHost: os.Getenv("DATABASE_HOST"),
@@ -72,8 +146,106 @@ func EnvFile() *Config {
 			SMTP_TOKEN_EMAIL:   os.Getenv("SMTP_TOKEN_EMAIL"),
 		},
 		Gmaps: GMAPS{
-			GMAPS_API_KEY: os.Getenv("GMAPS_API_KEY"),
+			GMAPS_API_KEY:     os.Getenv("GMAPS_API_KEY"),
+			GMAPS_MODE:        os.Getenv("GMAPS_MODE"),
+			GMAPS_FIXTURE_DIR: os.Getenv("GMAPS_FIXTURE_DIR"),
+			GMAPS_TIMEOUT:     envDuration("GMAPS_TIMEOUT"),
+			GMAPS_MAX_RETRIES: gmapsRetries,
+		},
+		GmapsCache: GMAPS_CACHE{
+			GMAPS_CACHE_SIZE:       cacheSize,
+			GMAPS_TTL_SEARCH:       envDuration("GMAPS_TTL_SEARCH"),
+			GMAPS_TTL_DETAILS:      envDuration("GMAPS_TTL_DETAILS"),
+			GMAPS_TTL_GEOCODE:      envDuration("GMAPS_TTL_GEOCODE"),
+			GMAPS_TTL_ROUTE:        envDuration("GMAPS_TTL_ROUTE"),
+			GMAPS_TTL_AUTOCOMPLETE: envDuration("GMAPS_TTL_AUTOCOMPLETE"),
+		},
+		GmapsQuota: GMAPS_QUOTA{
+			GMAPS_BUDGET_DAILY:   envIntMap("GMAPS_BUDGET_DAILY"),
+			GMAPS_BUDGET_MONTHLY: envIntMap("GMAPS_BUDGET_MONTHLY"),
+			GMAPS_BUDGET_WARN:    budgetWarn,
+			GMAPS_PRICES:         envFloatMap("GMAPS_PRICES"),
+		},
+		Photo: PHOTO{
+			PHOTO_CACHE_DIR:    os.Getenv("PHOTO_CACHE_DIR"),
+			PHOTO_CACHE_MAX_MB: photoCacheMB,
+			PHOTO_URL_SECRET:   os.Getenv("PHOTO_URL_SECRET"),
+			PHOTO_URL_TTL:      envDuration("PHOTO_URL_TTL"),
+			PHOTO_RATE_LIMIT:   photoRate,
+			PHOTO_RATE_BURST:   photoBurst,
+		},
+		Places: PLACES{
+			PLACES_PROVIDER: os.Getenv("PLACES_PROVIDER"),
+			NOMINATIM_URL:   os.Getenv("NOMINATIM_URL"),
+			NOMINATIM_EMAIL: os.Getenv("NOMINATIM_EMAIL"),
+		},
+		Routing: ROUTING{
+			ROUTING_PROVIDER: os.Getenv("ROUTING_PROVIDER"),
+			OSRM_URL:         os.Getenv("OSRM_URL"),
+		},
+		Auth: AUTH{
+			ACCESS_TOKEN_TTL:  envDuration("ACCESS_TOKEN_TTL"),
+			REFRESH_TOKEN_TTL: envDuration("REFRESH_TOKEN_TTL"),
+		},
+		Server: SERVER{
+			TRUSTED_PROXIES: envList("TRUSTED_PROXIES"),
 		},
 		URL_Server: os.Getenv("ENDPOINT_SERVER"),
 	}
 }
+
+// Format durasi mengikuti time.ParseDuration, contoh "24h" atau "5m"
+func envDuration(key string) time.Duration {
+	val := os.Getenv(key)
+	if val == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		log.Printf("Format %s tidak valid, pakai default: %s", key, err)
+		return 0
+	}
+	return d
+}
+
+// Format "details:1000,route:500"
+func envFloatMap(key string) map[string]float64 {
+	res := make(map[string]float64)
+	val := os.Getenv(key)
+	if val == "" {
+		return res
+	}
+	for _, pair := range strings.Split(val, ",") {
+		name, num, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			log.Printf("Format %s tidak valid: %s", key, pair)
+			continue
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+		if err != nil {
+			log.Printf("Format %s tidak valid: %s", key, pair)
+			continue
+		}
+		res[strings.TrimSpace(name)] = f
+	}
+	return res
+}
+
+func envIntMap(key string) map[string]int {
+	res := make(map[string]int)
+	for name, f := range envFloatMap(key) {
+		res[name] = int(f)
+	}
+	return res
+}
+
+// Format "127.0.0.1,10.0.0.0/8", kosong = nil
+func envList(key string) []string {
+	var res []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
@@ -2,15 +2,21 @@ package config
 
 import (
 	"os"
+	"proyek1/constant"
 	"proyek1/utils"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
-func NewJWT(log *logrus.Logger) utils.JWTInterface {
+func NewJWT(c *Config, log *logrus.Logger) utils.JWTInterface {
 	secret := os.Getenv("JWT_SECRET")
 	if secret == "" {
 		log.Error("Gagal jwt secret")
 	}
-	return utils.NewJWT(secret)
+	ttl := c.Auth.ACCESS_TOKEN_TTL
+	if ttl <= 0 {
+		ttl = time.Duration(constant.AccessTokenTTL) * time.Minute
+	}
+	return utils.NewJWT(secret, ttl)
 }
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"proyek1/constant"
+	"proyek1/utils"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Kalau PHOTO_URL_SECRET kosong dibuat secret acak, URL foto lama jadi tidak valid setiap restart
+func NewPhotoSigner(c *Config, log *logrus.Logger) utils.PhotoSignerInterface {
+	secret := c.Photo.PHOTO_URL_SECRET
+	if secret == "" {
+		log.Warn("PHOTO_URL_SECRET kosong, pakai secret acak")
+		b := make([]byte, 32)
+		rand.Read(b)
+		secret = hex.EncodeToString(b)
+	}
+	ttl := c.Photo.PHOTO_URL_TTL
+	if ttl <= 0 {
+		ttl = time.Duration(constant.PhotoURLTTL) * time.Hour
+	}
+	return utils.NewPhotoSigner(secret, c.URL_Server, ttl)
+}
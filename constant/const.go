@@ -1,13 +1,122 @@
 package constant
 
 const (
-	Gmaps                  = "https://maps.googleapis.com/maps/api/place/findplacefromtext/json?fields=place_id,name,geometry&input=" // findplcaefromtext = return object, jadi pakai textsearch  kalau array
-	GmapsSearchText        = "https://maps.googleapis.com/maps/api/place/textsearch/json"                                             // findplcaefromtext = return object, jadi pakai textsearch  kalau array
+	Gmaps                  = "https://maps.googleapis.com/maps/api/place/findplacefromtext/json?fields=place_id,name,geometry,types&input=" // findplcaefromtext = return object, jadi pakai textsearch  kalau array
+	GmapsSearchText        = "https://maps.googleapis.com/maps/api/place/textsearch/json"                                                   // findplcaefromtext = return object, jadi pakai textsearch  kalau array
 	GmapsGetByPlaceID      = "https://maps.googleapis.com/maps/api/place/details/json?place_id"
 	GmapsGetRouteByPlaceID = "https://routes.googleapis.com/directions/v2:computeRoutes"
+	GmapsGeocode           = "https://maps.googleapis.com/maps/api/geocode/json"
+	GmapsPhoto             = "https://maps.googleapis.com/maps/api/place/photo"
+	GmapsAutocomplete      = "https://maps.googleapis.com/maps/api/place/autocomplete/json"
+	NominatimURL           = "https://nominatim.openstreetmap.org"
 	VercelRoute            = "https://html-411k7ckwk-chands-projects-5f68fc9c.vercel.app/static/index.html"
 
+	// Hanya field yang di-parse ke model.ResponseRouteMaps
+	GmapsRouteFieldMask = "routes.distanceMeters,routes.duration,routes.polyline.encodedPolyline," +
+		"routes.legs.distanceMeters,routes.legs.duration,routes.legs.startLocation,routes.legs.endLocation," +
+		"routes.legs.steps.distanceMeters,routes.legs.steps.staticDuration,routes.legs.steps.polyline.encodedPolyline," +
+		"routes.legs.steps.navigationInstruction,routes.legs.steps.travelMode,routes.legs.steps.transitDetails"
+
+	// Travel mode Routes API
+	TravelDrive      = "DRIVE"
+	TravelTwoWheeler = "TWO_WHEELER" // motor
+	TravelWalk       = "WALK"
+	TravelBicycle    = "BICYCLE"
+	TravelTransit    = "TRANSIT"
+	MaxAlternatives  = 3
+
+	// Geohash
+	GeohashPrecision = 9  // ~5m, disimpan di tempat_pariwisata.geohash
+	GeohashMaxCells  = 32 // batas jumlah prefix saat mempersempit query bbox
+
+	// business_status tempat. NOT_FOUND bukan dari Google, ditandai sendiri waktu resync
+	// kalau place_id sudah tidak dikenal
+	BusinessOperational       = "OPERATIONAL"
+	BusinessClosedTemporarily = "CLOSED_TEMPORARILY"
+	BusinessClosedPermanently = "CLOSED_PERMANENTLY"
+	BusinessNotFound          = "NOT_FOUND"
+
+	// Sesi login
+	AccessTokenTTL  = 15 // menit
+	RefreshTokenTTL = 30 // hari
+
+	// Alasan sesi dicabut (user_sessions.revoked_reason, VARCHAR tanpa CHECK).
+	// Daftar lengkapnya di sini, komentar kolom di migrasi 005 hanya memuat nilai awal
+	RevokeLogout    = "logout"
+	RevokeLogoutAll = "logout_all"
+	RevokeReuse     = "reuse"           // refresh token lama dipakai lagi
+	RevokeUser      = "revoked_by_user" // user mencabut satu sesi lewat DELETE /sessions/:id
+	RevokePassword  = "password_reset"  // semua sesi dicabut setelah reset password
+
+	// Token sekali pakai (user_tokens.purpose)
+	PurposeActivate    = "activate"
+	PurposeReset       = "reset"
+	PurposeChangeEmail = "change_email"
+	ActivateTokenTTL   = 24 // jam
+	ResetTokenTTL      = 15 // menit, dihitung dari OTP benar
+	ChangeEmailTTL     = 60 // menit
+
+	// Resync tempat
+	ResyncDefaultLimit = 20
+	ResyncMaxLimit     = 100
+
+	// Viewport map
+	ViewportMaxFeatures = 500 // batas jumlah titik/cluster per response
+	ViewportClusterZoom = 14  // zoom >= ini tampil per tempat, di bawahnya di-cluster
+
+	// Foto
+	PhotoDefaultWidth = 400
+	PhotoCacheMaxAge  = 7 * 24 * 60 * 60 // detik, untuk header Cache-Control
+	PhotoCacheDir     = "./cache/photos"
+	PhotoCacheMaxMB   = 512
+	PhotoURLTTL       = 24  // jam, masa berlaku URL foto yang ditandatangani
+	PhotoRateLimit    = 120 // request per menit per IP
+	PhotoRateBurst    = 30
+
+	// Client gmaps
+	GmapsTimeout         = 10   // detik per request
+	GmapsRetries         = 2    // retry untuk 5xx/429/OVER_QUERY_LIMIT
+	GmapsRetryBase       = 200  // ms, backoff awal
+	GmapsRetryMax        = 2000 // ms, batas backoff
+	GmapsBreakerFailures = 5    // gagal berturut-turut sebelum breaker terbuka
+	GmapsBreakerCooldown = 30   // detik breaker terbuka
+
+	// Quota gmaps
+	GmapsBudgetWarn   = 80 // persen
+	GmapsUsageTopUser = 10
+
+	// Pencarian tempat
+	SearchDefaultLanguage = "id"
+	SearchDefaultRadius   = 5000  // meter, kalau lat/lng dikirim tanpa radius
+	SearchMaxRadius       = 50000 // batas Places API
+	SearchPageTokenDelay  = 2     // detik, next_page_token Google baru bisa dipakai beberapa saat setelah dibuat
+	SearchPageTokenTries  = 3
+
+	// Level wilayah untuk /regions
+	RegionProvince = "province"
+	RegionRegency  = "regency"
+	RegionDistrict = "district"
+
 	// Message Response
 	StatusSuccess = "success"
 	StatusFail    = "fail"
 )
+
+// Ukuran foto yang boleh diminta lewat maxwidth/maxheight, dibatasi supaya cache tidak pecah ke banyak ukuran
+var PhotoSizes = []int{100, 200, 400, 800, 1600}
+
+// Bahasa hasil pencarian/detail yang didukung client
+var SearchLanguages = []string{"id", "en"}
+
+// Perkiraan harga Google Maps (USD per 1000 panggilan), bisa ditimpa lewat GMAPS_PRICES
+var GmapsPrices = map[string]float64{
+	"search":  17,
+	"list":    32,
+	"details": 17,
+	"photo":   7,
+	"route":   5,
+	"geocode": 5,
+	"reverse": 5,
+	// per request, kalau sesi ditutup dengan details yang dihitung hanya details
+	"autocomplete": 2.83,
+}
@@ -0,0 +1,52 @@
+ALTER TABLE tempat_pariwisata ADD COLUMN IF NOT EXISTS geohash VARCHAR(12);
+
+CREATE INDEX IF NOT EXISTS idx_tempat_geohash ON tempat_pariwisata (geohash varchar_pattern_ops);
+
+-- Sama dengan utils/geo.Encode, dipakai untuk backfill data lama
+CREATE OR REPLACE FUNCTION geohash_encode(p_lat DOUBLE PRECISION, p_lng DOUBLE PRECISION, p_len INT)
+RETURNS VARCHAR AS $$
+DECLARE
+    base32 CONSTANT TEXT := '0123456789bcdefghjkmnpqrstuvwxyz';
+    lat_min DOUBLE PRECISION := -90;
+    lat_max DOUBLE PRECISION := 90;
+    lng_min DOUBLE PRECISION := -180;
+    lng_max DOUBLE PRECISION := 180;
+    mid DOUBLE PRECISION;
+    hash TEXT := '';
+    bit_count INT := 0;
+    ch INT := 0;
+    even BOOLEAN := TRUE;
+BEGIN
+    WHILE length(hash) < p_len LOOP
+        IF even THEN
+            mid := (lng_min + lng_max) / 2;
+            IF p_lng >= mid THEN
+                ch := ch * 2 + 1;
+                lng_min := mid;
+            ELSE
+                ch := ch * 2;
+                lng_max := mid;
+            END IF;
+        ELSE
+            mid := (lat_min + lat_max) / 2;
+            IF p_lat >= mid THEN
+                ch := ch * 2 + 1;
+                lat_min := mid;
+            ELSE
+                ch := ch * 2;
+                lat_max := mid;
+            END IF;
+        END IF;
+        even := NOT even;
+        bit_count := bit_count + 1;
+        IF bit_count = 5 THEN
+            hash := hash || substr(base32, ch + 1, 1);
+            bit_count := 0;
+            ch := 0;
+        END IF;
+    END LOOP;
+    RETURN hash;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+UPDATE tempat_pariwisata SET geohash = geohash_encode(latitude, longtitude, 9) WHERE geohash IS NULL;
@@ -0,0 +1,2 @@
+-- Sumber data tempat: google atau osm. place_id OSM diberi prefix "osm:" jadi tetap unique lintas provider
+ALTER TABLE tempat_pariwisata ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'google';
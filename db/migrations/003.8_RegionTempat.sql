@@ -0,0 +1,8 @@
+-- Wilayah administrasi dari address components Google/OSM, dibakukan lewat utils/region.
+-- Data lama tetap NULL sampai tempatnya diimport ulang
+ALTER TABLE tempat_pariwisata ADD COLUMN IF NOT EXISTS province_code VARCHAR(2);
+ALTER TABLE tempat_pariwisata ADD COLUMN IF NOT EXISTS province VARCHAR(100);
+ALTER TABLE tempat_pariwisata ADD COLUMN IF NOT EXISTS regency VARCHAR(150);
+ALTER TABLE tempat_pariwisata ADD COLUMN IF NOT EXISTS district VARCHAR(150);
+
+CREATE INDEX IF NOT EXISTS idx_tempat_region ON tempat_pariwisata (province_code, lower(regency), lower(district));
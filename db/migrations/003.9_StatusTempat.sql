@@ -0,0 +1,11 @@
+-- FK ke place_id dibuat deferrable supaya place_id yang dipindah Google (resync) bisa diganti
+-- di tempat_pariwisata dan semua tabel anak dalam satu transaksi
+ALTER TABLE review_tempat ALTER CONSTRAINT fk_review_place DEFERRABLE INITIALLY IMMEDIATE;
+ALTER TABLE foto_tempat ALTER CONSTRAINT fk_photo_place DEFERRABLE INITIALLY IMMEDIATE;
+ALTER TABLE opening_hours ALTER CONSTRAINT fk_opening_place DEFERRABLE INITIALLY IMMEDIATE;
+ALTER TABLE category_pariwisata ALTER CONSTRAINT category_pariwisata_place_id_fkey DEFERRABLE INITIALLY IMMEDIATE;
+
+-- Terakhir dicek ulang ke Google/OSM, NULL = belum pernah
+ALTER TABLE tempat_pariwisata ADD COLUMN IF NOT EXISTS synced_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_tempat_synced_at ON tempat_pariwisata (synced_at NULLS FIRST) WHERE deleted_at IS NULL;
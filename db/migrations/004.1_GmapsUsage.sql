@@ -0,0 +1,9 @@
+-- Jumlah panggilan ke Google Maps per hari, jenis panggilan dan user ('' untuk request tanpa login, contoh /photo)
+CREATE TABLE IF NOT EXISTS gmaps_usage (
+    day DATE NOT NULL,
+    endpoint VARCHAR(20) NOT NULL,
+    user_id VARCHAR(50) NOT NULL DEFAULT '',
+    calls INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (day, endpoint, user_id)
+);
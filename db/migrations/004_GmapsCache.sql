@@ -0,0 +1,10 @@
+-- Cache response Google Maps (tahap kedua setelah LRU memory), key = jenis:sha1(input)
+CREATE TABLE IF NOT EXISTS gmaps_cache (
+    key VARCHAR(64) PRIMARY KEY NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    value JSONB NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_gmaps_cache_expires_at ON gmaps_cache (expires_at);
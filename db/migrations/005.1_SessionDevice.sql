@@ -0,0 +1,2 @@
+-- Nama perangkat untuk daftar sesi, dari body login (device_name) atau ditebak dari user agent
+ALTER TABLE user_sessions ADD COLUMN IF NOT EXISTS device_name VARCHAR(100);
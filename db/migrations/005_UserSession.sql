@@ -0,0 +1,36 @@
+-- Satu baris per login (perangkat). Access token membawa id sesi (claim sid),
+-- sesi yang dicabut langsung ditolak middleware auth
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id VARCHAR(50) PRIMARY KEY NOT NULL,
+    users_id VARCHAR(50) NOT NULL,
+    user_agent VARCHAR(255),
+    ip_address VARCHAR(64),
+    expires_at TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ DEFAULT NOW(),
+    revoked_at TIMESTAMPTZ DEFAULT NULL,
+    revoked_reason VARCHAR(50), -- logout, logout_all, reuse
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+
+    CONSTRAINT fk_session_user FOREIGN KEY (users_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_session_user ON user_sessions (users_id) WHERE revoked_at IS NULL;
+
+-- Refresh token diputar setiap dipakai. Token lama tetap disimpan (used_at terisi),
+-- kalau token lama dipakai lagi berarti bocor dan seluruh sesi dicabut
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    token_hash VARCHAR(64) PRIMARY KEY NOT NULL, -- sha256 hex, token asli tidak disimpan
+    session_id VARCHAR(50) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ DEFAULT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+
+    CONSTRAINT fk_refresh_session FOREIGN KEY (session_id)
+    REFERENCES user_sessions(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_session ON refresh_tokens (session_id);
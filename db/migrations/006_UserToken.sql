@@ -0,0 +1,17 @@
+-- Token sekali pakai per keperluan (activate, reset, change_email), terpisah dari token login.
+-- Yang disimpan hanya hash-nya, token dianggap terpakai begitu used_at terisi
+CREATE TABLE IF NOT EXISTS user_tokens (
+    token_hash VARCHAR(64) PRIMARY KEY NOT NULL,
+    users_id VARCHAR(50) NOT NULL,
+    purpose VARCHAR(20) NOT NULL,
+    payload VARCHAR(255), -- contoh email baru untuk change_email
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ DEFAULT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+
+    CONSTRAINT fk_token_user FOREIGN KEY (users_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_tokens_user ON user_tokens (users_id, purpose) WHERE used_at IS NULL;
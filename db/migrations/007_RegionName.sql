@@ -0,0 +1,13 @@
+-- Kabupaten/kota dan kecamatan disimpan tanpa penanda (Kabupaten, Kota, Kecamatan, Regency, City, District)
+-- supaya cocok dengan utils/region.NormalizeRegency/NormalizeDistrict. Aman dijalankan ulang
+UPDATE tempat_pariwisata
+SET regency = btrim(regexp_replace(regexp_replace(regency,
+        '^\s*(kabupaten|kab\.?|kota administrasi|kota adm\.|kota)\s+', '', 'i'),
+        '\s+(regency|city)\s*$', '', 'i'))
+WHERE regency ~* '^\s*(kabupaten|kab\.?|kota)\s' OR regency ~* '\s(regency|city)\s*$';
+
+UPDATE tempat_pariwisata
+SET district = btrim(regexp_replace(regexp_replace(district,
+        '^\s*(kecamatan|kec\.?)\s+', '', 'i'),
+        '\s+(subdistrict|sub-district|district)\s*$', '', 'i'))
+WHERE district ~* '^\s*(kecamatan|kec\.?)\s' OR district ~* '\s(subdistrict|sub-district|district)\s*$';
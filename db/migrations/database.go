@@ -18,6 +18,16 @@ func CreateTables(db *sql.DB) error {
 		"./db/migrations/003.3_OpeningHours.sql",
 		"./db/migrations/003.4_CategoryMaster.sql",
 		"./db/migrations/003.5_CategoryPariwisata.sql",
+		"./db/migrations/003.6_GeohashTempat.sql",
+		"./db/migrations/003.7_SourceTempat.sql",
+		"./db/migrations/003.8_RegionTempat.sql",
+		"./db/migrations/003.9_StatusTempat.sql",
+		"./db/migrations/004_GmapsCache.sql",
+		"./db/migrations/004.1_GmapsUsage.sql",
+		"./db/migrations/005_UserSession.sql",
+		"./db/migrations/005.1_SessionDevice.sql",
+		"./db/migrations/006_UserToken.sql",
+		"./db/migrations/007_RegionName.sql",
 	}
 
 	for _, v := range files {
@@ -0,0 +1,55 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"proyek1/constant"
+	"proyek1/internal/delivery/middleware"
+	"proyek1/internal/model"
+	"proyek1/utils"
+	crypto "proyek1/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type GmapsUsageUsecaseInterface interface {
+	GetGmapsUsage(ctx context.Context, from, to string) (model.GmapsUsageReport, error)
+}
+
+type GmapsUsageHandlerInterface interface {
+	GetGmapsUsage(c *gin.Context)
+}
+
+type GmapsUsageHandler struct {
+	uc  GmapsUsageUsecaseInterface
+	log *logrus.Logger
+}
+
+func NewGmapsUsageHandler(uc GmapsUsageUsecaseInterface, log *logrus.Logger) *GmapsUsageHandler {
+	return &GmapsUsageHandler{
+		uc:  uc,
+		log: log,
+	}
+}
+
+// Khusus admin: jumlah panggilan Google Maps dan perkiraan biaya, ?from=YYYY-MM-DD&to=YYYY-MM-DD
+func (h *GmapsUsageHandler) GetGmapsUsage(c *gin.Context) {
+	dataToken, ok := middleware.GetUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "Unauthorize", nil))
+		return
+	}
+
+	if !crypto.IsAdmin(dataToken.Role) {
+		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "error unknown token data", nil))
+		return
+	}
+
+	data, err := h.uc.GetGmapsUsage(c.Request.Context(), c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ResponseHandler(constant.StatusFail, err.Error(), nil))
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseHandler(constant.StatusSuccess, "Berhasil mendapatkan data", data))
+}
@@ -2,6 +2,9 @@ package delivery
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"proyek1/constant"
@@ -11,8 +14,11 @@ import (
 	crypto "proyek1/utils"
 	jwt "proyek1/utils"
 	"proyek1/utils/gmaps"
+	"proyek1/utils/places"
+	"proyek1/utils/region"
+	"regexp"
 	"strconv"
-	"time"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -21,31 +27,47 @@ type MapsHandlerInterface interface {
 	GmapsSearchbyObject(c *gin.Context)
 	GmapsSearchbyList(c *gin.Context)
 	GmapsSearchbyPlaceID(c *gin.Context)
+	Autocomplete(c *gin.Context)
+	Geocode(c *gin.Context)
+	ReverseGeocode(c *gin.Context)
 
 	InsertData(c *gin.Context)
 	GetTempatPagination(c *gin.Context)
 	ProxyPhotoHandler(c *gin.Context)
 	RouteDestination(c *gin.Context)
 	GetDetailTempat(c *gin.Context)
+	GetTempatGeoJSON(c *gin.Context)
+	GetTempatViewport(c *gin.Context)
+	GetRegions(c *gin.Context)
+	ResyncTempat(c *gin.Context)
 }
 
 type MapsUsecaseInterface interface {
 	InsertTempat(ctx context.Context, placeId string) error
-	GetTempatPagination(ctx context.Context, name string, limit, page int) ([]model.GetAllTempat, int, error)
-	RouteDestination(ctx context.Context, req model.RequestRouteMaps, placeID string) (*model.ResponseRouteMaps, error)
+	Autocomplete(ctx context.Context, input string, opts model.SearchOptions) ([]model.Prediction, error)
+	GetTempatPagination(ctx context.Context, filter model.FilterTempat, limit, page int) ([]model.GetAllTempat, int, error)
+	GetRegions(ctx context.Context, filter model.FilterTempat) (model.Regions, error)
+	ResyncTempat(ctx context.Context, placeID string, limit int) ([]model.ResyncTempat, error)
+	RouteDestination(ctx context.Context, req model.RequestRouteOptions, placeID string) (*model.ResponseRouteMaps, error)
 	GetDetailTempat(ctx context.Context, id string) (model.GetDetailTempat, error)
+	StreamTempatGeoJSON(ctx context.Context, filter model.FilterTempat, fn func(model.GeoJSONFeature) error) error
+	GetTempatViewport(ctx context.Context, bbox model.BBox, zoom int) (model.ViewportTempat, error)
 }
 type MapsHandler struct {
-	jwt   jwt.JWTInterface
-	gmaps gmaps.GmapsInterface
-	us    MapsUsecaseInterface
+	jwt    jwt.JWTInterface
+	gmaps  gmaps.GmapsInterface
+	places places.Provider
+	photo  utils.PhotoSignerInterface
+	us     MapsUsecaseInterface
 }
 
-func NewMapsHandler(jwt jwt.JWTInterface, gmaps gmaps.GmapsInterface, us MapsUsecaseInterface) MapsHandler {
+func NewMapsHandler(jwt jwt.JWTInterface, gmaps gmaps.GmapsInterface, places places.Provider, photo utils.PhotoSignerInterface, us MapsUsecaseInterface) MapsHandler {
 	return MapsHandler{
-		jwt:   jwt,
-		gmaps: gmaps,
-		us:    us,
+		jwt:    jwt,
+		gmaps:  gmaps,
+		places: places,
+		photo:  photo,
+		us:     us,
 	}
 }
 
@@ -67,10 +89,16 @@ func (h *MapsHandler) GmapsSearchbyObject(c *gin.Context) {
 		return
 	}
 
-	// Panggil langsung service/fungsi GmapsAdd
-	results, err := h.gmaps.GmapsSearchObject(query)
+	opts, err := parseSearchOptions(c)
 	if err != nil {
-		c.JSON(utils.ConverResponse(err), utils.ResponseHandler(constant.StatusFail, err.Error(), nil))
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+
+	// Panggil langsung provider pencarian tempat (google / osm)
+	results, err := h.places.SearchObject(c.Request.Context(), query, opts)
+	if err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
 		return
 	}
 
@@ -95,12 +123,26 @@ func (h *MapsHandler) GmapsSearchbyList(c *gin.Context) {
 		return
 	}
 
-	results, err := h.gmaps.GmapsSearchList(query)
+	opts, err := parseSearchOptions(c)
 	if err != nil {
-		c.JSON(utils.ConverResponse(err), utils.ResponseHandler(constant.StatusFail, err.Error(), nil))
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
 		return
 	}
 
+	// token dari next_page_token response sebelumnya, query harus sama
+	opts.PageToken = c.Query("pageToken")
+
+	results, err := h.places.SearchList(c.Request.Context(), query, opts)
+	if err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+	for _, r := range results.Results {
+		if r.Photo != nil {
+			r.Photo.PhotoURL = h.photo.SignPhoto(r.Photo.PhotoReference, constant.PhotoDefaultWidth, 0)
+		}
+	}
+
 	c.JSON(http.StatusCreated, utils.ResponseHandler(constant.StatusSuccess, "Berhasil mendapatkan data", results))
 }
 
@@ -122,15 +164,134 @@ func (h *MapsHandler) GmapsSearchbyPlaceID(c *gin.Context) {
 		return
 	}
 
-	results, err := h.gmaps.GmapsSearchByPlaceID(placeId)
+	language, err := parseLanguage(c.Query("language"))
 	if err != nil {
-		c.JSON(utils.ConverResponse(err), utils.ResponseHandler(constant.StatusFail, err.Error(), nil))
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+	// detail setelah autocomplete dengan session yang sama menutup sesi di Google
+	session, err := parseSession(c.Query("session"))
+	if err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
 		return
 	}
 
+	ctx := utils.WithPlacesSession(c.Request.Context(), session)
+	results, err := h.places.Details(ctx, placeId, language)
+	if err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+	for i, p := range results.Photos {
+		results.Photos[i].PhotoURL = h.photo.SignPhoto(p.PhotoReference, constant.PhotoDefaultWidth, 0)
+	}
+
 	c.JSON(http.StatusCreated, utils.ResponseHandler(constant.StatusSuccess, "Berhasil mendapatkan data", results))
 }
 
+// Prediksi tempat per ketikan, client membuat satu session (contoh UUID) per sesi pencarian
+// lalu memakai session yang sama saat membuka /place/:id
+func (h *MapsHandler) Autocomplete(c *gin.Context) {
+	dataToken, ok := middleware.GetUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "Unauthorize", nil))
+		return
+	}
+
+	if !crypto.IsUser(dataToken.Role) {
+		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "error unknown token data", nil))
+		return
+	}
+
+	input := strings.TrimSpace(c.Query("input"))
+	if input == "" {
+		c.JSON(http.StatusBadRequest, utils.ResponseHandler(constant.StatusFail, "input tidak boleh kosong", nil))
+		return
+	}
+
+	session, err := parseSession(c.Query("session"))
+	if err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+	opts, err := parseSearchOptions(c)
+	if err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+
+	ctx := utils.WithPlacesSession(c.Request.Context(), session)
+	results, err := h.us.Autocomplete(ctx, input, opts)
+	if err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseHandler(constant.StatusSuccess, "Berhasil mendapatkan data", results))
+}
+
+// Alamat yang diketik user ke koordinat
+func (h *MapsHandler) Geocode(c *gin.Context) {
+	dataToken, ok := middleware.GetUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "Unauthorize", nil))
+		return
+	}
+
+	if !crypto.IsUser(dataToken.Role) {
+		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "error unknown token data", nil))
+		return
+	}
+
+	address := strings.TrimSpace(c.Query("address"))
+	if address == "" {
+		c.JSON(http.StatusBadRequest, utils.ResponseHandler(constant.StatusFail, "address tidak boleh kosong", nil))
+		return
+	}
+
+	results, err := h.places.Geocode(c.Request.Context(), address)
+	if err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+	if results == nil {
+		results = []model.GeocodeResult{}
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseHandler(constant.StatusSuccess, "Berhasil mendapatkan data", results))
+}
+
+// Posisi GPS ke alamat (contoh untuk origin rute), hasil pertama yang paling spesifik
+func (h *MapsHandler) ReverseGeocode(c *gin.Context) {
+	dataToken, ok := middleware.GetUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "Unauthorize", nil))
+		return
+	}
+
+	if !crypto.IsUser(dataToken.Role) {
+		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "error unknown token data", nil))
+		return
+	}
+
+	location, err := parseLatLng(c.Query("lat"), c.Query("lng"))
+	if err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+
+	results, err := h.places.ReverseGeocode(c.Request.Context(), location.Latitude, location.Longitude)
+	if err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+	if results == nil {
+		results = []model.GeocodeResult{}
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseHandler(constant.StatusSuccess, "Berhasil mendapatkan data", results))
+}
+
 func (h *MapsHandler) InsertData(c *gin.Context) {
 	dataToken, ok := middleware.GetUser(c)
 	if !ok {
@@ -152,7 +313,7 @@ func (h *MapsHandler) InsertData(c *gin.Context) {
 	ctx := c.Request.Context()
 	err := h.us.InsertTempat(ctx, placeId)
 	if err != nil {
-		c.JSON(utils.ConverResponse(err), utils.ResponseHandler(constant.StatusFail, err.Error(), nil))
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
 		return
 	}
 
@@ -180,11 +341,16 @@ func (h *MapsHandler) GetTempatPagination(c *gin.Context) {
 		page = 1
 	}
 
-	n := c.Query("search")
+	filter, err := parseFilterTempat(c)
+	if err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+
 	ctx := c.Request.Context()
-	res, pageTotal, err := h.us.GetTempatPagination(ctx, n, 5, int(page))
+	res, pageTotal, err := h.us.GetTempatPagination(ctx, filter, 5, int(page))
 	if err != nil {
-		c.JSON(utils.ConverResponse(err), utils.ResponseHandler(constant.StatusFail, err.Error(), nil))
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
 		return
 	}
 
@@ -211,12 +377,312 @@ func (h *MapsHandler) GetDetailTempat(c *gin.Context) {
 	id := c.Param("id")
 	data, err := h.us.GetDetailTempat(ctx, id)
 	if err != nil {
-		c.JSON(utils.ConverResponse(err), utils.ResponseHandler(constant.StatusFail, err.Error(), nil))
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
 		return
 	}
 	c.JSON(http.StatusOK, utils.ResponseHandler(constant.StatusSuccess, "Berhasil mendapatkan data", data))
 }
 
+// Semua tempat aktif dalam bentuk GeoJSON FeatureCollection, ditulis per baris (streaming)
+func (h *MapsHandler) GetTempatGeoJSON(c *gin.Context) {
+	dataToken, ok := middleware.GetUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "Unauthorize", nil))
+		return
+	}
+
+	if !crypto.IsUser(dataToken.Role) {
+		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "error unknown token data", nil))
+		return
+	}
+
+	filter, err := parseFilterTempat(c)
+	if err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+	if b := c.Query("bbox"); b != "" {
+		bbox, err := parseBBox(b)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, utils.ResponseHandler(constant.StatusFail, err.Error(), nil))
+			return
+		}
+		filter.BBox = bbox
+	}
+
+	ctx := c.Request.Context()
+	started := false
+	err = h.us.StreamTempatGeoJSON(ctx, filter, func(f model.GeoJSONFeature) error {
+		data, err := json.Marshal(f)
+		if err != nil {
+			return err
+		}
+		if !started {
+			c.Header("Content-Type", "application/geo+json")
+			c.Status(http.StatusOK)
+			if _, err := io.WriteString(c.Writer, `{"type":"FeatureCollection","features":[`); err != nil {
+				return err
+			}
+			started = true
+		} else if _, err := io.WriteString(c.Writer, ","); err != nil {
+			return err
+		}
+		if _, err := c.Writer.Write(data); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		if !started {
+			c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+			return
+		}
+		// header sudah terkirim, response sengaja dibiarkan terpotong supaya client tahu gagal
+		c.Error(err)
+		return
+	}
+
+	if !started {
+		c.Header("Content-Type", "application/geo+json")
+		c.Status(http.StatusOK)
+		io.WriteString(c.Writer, `{"type":"FeatureCollection","features":[`)
+	}
+	io.WriteString(c.Writer, "]}")
+}
+
+func (h *MapsHandler) GetTempatViewport(c *gin.Context) {
+	dataToken, ok := middleware.GetUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "Unauthorize", nil))
+		return
+	}
+
+	if !crypto.IsUser(dataToken.Role) {
+		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "error unknown token data", nil))
+		return
+	}
+
+	bbox, err := parseBBox(strings.Join([]string{c.Query("minLng"), c.Query("minLat"), c.Query("maxLng"), c.Query("maxLat")}, ","))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ResponseHandler(constant.StatusFail, err.Error(), nil))
+		return
+	}
+
+	zoom, err := strconv.Atoi(c.Query("zoom"))
+	if err != nil || zoom < 0 || zoom > 22 {
+		c.JSON(http.StatusBadRequest, utils.ResponseHandler(constant.StatusFail, "zoom harus angka 0 sampai 22", nil))
+		return
+	}
+
+	ctx := c.Request.Context()
+	data, err := h.us.GetTempatViewport(ctx, *bbox, zoom)
+	if err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseHandler(constant.StatusSuccess, "Berhasil mendapatkan data", data))
+}
+
+var (
+	placeTypePattern = regexp.MustCompile(`^[a-z_]+$`)
+	sessionPattern   = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+)
+
+// Session boleh kosong (tiap request dihitung terpisah oleh Google)
+func parseSession(val string) (string, error) {
+	if val != "" && !sessionPattern.MatchString(val) {
+		return "", utils.ErrSessionToken
+	}
+	return val, nil
+}
+
+// Query lat, lng, radius (meter), region, type dan language untuk /maps dan /maps-list
+func parseSearchOptions(c *gin.Context) (model.SearchOptions, error) {
+	var opts model.SearchOptions
+
+	lat, lng := c.Query("lat"), c.Query("lng")
+	if lat != "" || lng != "" {
+		location, err := parseLatLng(lat, lng)
+		if err != nil {
+			return opts, err
+		}
+		opts.Location = location
+	}
+
+	if r := c.Query("radius"); r != "" {
+		radius, err := strconv.Atoi(r)
+		if err != nil || radius < 1 || radius > constant.SearchMaxRadius || opts.Location == nil {
+			return opts, utils.ErrSearchRadius
+		}
+		opts.Radius = radius
+	}
+
+	if region := strings.ToLower(c.Query("region")); region != "" {
+		if len(region) != 2 || region[0] < 'a' || region[0] > 'z' || region[1] < 'a' || region[1] > 'z' {
+			return opts, utils.ErrSearchRegion
+		}
+		opts.Region = region
+	}
+
+	if t := c.Query("type"); t != "" {
+		if !placeTypePattern.MatchString(t) {
+			return opts, utils.ErrSearchType
+		}
+		opts.Type = t
+	}
+
+	language, err := parseLanguage(c.Query("language"))
+	if err != nil {
+		return opts, err
+	}
+	opts.Language = language
+	return opts, nil
+}
+
+func parseLatLng(lat, lng string) (*model.LatLng, error) {
+	latVal, errLat := strconv.ParseFloat(lat, 64)
+	lngVal, errLng := strconv.ParseFloat(lng, 64)
+	if errLat != nil || errLng != nil || latVal < -90 || latVal > 90 || lngVal < -180 || lngVal > 180 {
+		return nil, utils.ErrSearchLocation
+	}
+	return &model.LatLng{Latitude: latVal, Longitude: lngVal}, nil
+}
+
+// Kosong berarti bahasa default, selain itu harus salah satu dari constant.SearchLanguages
+func parseLanguage(val string) (string, error) {
+	if val == "" {
+		return "", nil
+	}
+	val = strings.ToLower(val)
+	for _, v := range constant.SearchLanguages {
+		if v == val {
+			return val, nil
+		}
+	}
+	return "", utils.ErrSearchLanguage
+}
+
+// Jumlah tempat per wilayah: tanpa parameter per provinsi, ?province= per kabupaten/kota,
+// ?province=&regency= per kecamatan
+func (h *MapsHandler) GetRegions(c *gin.Context) {
+	dataToken, ok := middleware.GetUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "Unauthorize", nil))
+		return
+	}
+
+	if !crypto.IsUser(dataToken.Role) {
+		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "error unknown token data", nil))
+		return
+	}
+
+	filter, err := parseFilterTempat(c)
+	if err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+	if filter.Regency != "" && filter.ProvinceCode == "" {
+		c.JSON(utils.ConverResponse(utils.ErrRegionRegency), utils.ErrorResponseHandler(utils.ErrRegionRegency))
+		return
+	}
+	filter.District = ""
+
+	ctx := c.Request.Context()
+	data, err := h.us.GetRegions(ctx, filter)
+	if err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseHandler(constant.StatusSuccess, "Berhasil mendapatkan data", data))
+}
+
+// Filter list tempat dari query: search, province, regency, district dan closed.
+// province boleh kode Kemendagri ("32") atau nama ("Jawa Barat", "West Java"),
+// regency dan district dibakukan sama seperti waktu tempat disimpan.
+// closed=true ikut menampilkan tempat yang tutup permanen atau sudah tidak ada di Google
+func parseFilterTempat(c *gin.Context) (model.FilterTempat, error) {
+	filter := model.FilterTempat{
+		Name: c.Query("search"),
+	}
+	if p := strings.TrimSpace(c.Query("province")); p != "" {
+		province, ok := region.FindProvince(p)
+		if !ok {
+			return filter, utils.ErrRegionProvince
+		}
+		filter.ProvinceCode = province.Code
+	}
+	if r := strings.TrimSpace(c.Query("regency")); r != "" {
+		filter.Regency = region.NormalizeRegency(r)
+	}
+	if d := strings.TrimSpace(c.Query("district")); d != "" {
+		filter.District = region.NormalizeDistrict(d)
+	}
+	if cl := c.Query("closed"); cl != "" {
+		closed, err := strconv.ParseBool(cl)
+		if err != nil {
+			return filter, utils.ErrClosedParam
+		}
+		filter.IncludeClosed = closed
+	}
+	return filter, nil
+}
+
+// Admin: cek ulang tempat tersimpan ke Google/OSM. ?id= untuk satu tempat (place_id),
+// tanpa id sebanyak ?limit= tempat yang paling lama belum dicek
+func (h *MapsHandler) ResyncTempat(c *gin.Context) {
+	dataToken, ok := middleware.GetUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "Unauthorize", nil))
+		return
+	}
+
+	if !crypto.IsAdmin(dataToken.Role) {
+		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "error unknown token data", nil))
+		return
+	}
+
+	limit := constant.ResyncDefaultLimit
+	if l := c.Query("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 || n > constant.ResyncMaxLimit {
+			c.JSON(utils.ConverResponse(utils.ErrResyncLimit), utils.ErrorResponseHandler(utils.ErrResyncLimit))
+			return
+		}
+		limit = n
+	}
+
+	ctx := c.Request.Context()
+	data, err := h.us.ResyncTempat(ctx, strings.TrimSpace(c.Query("id")), limit)
+	if err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseHandler(constant.StatusSuccess, "Berhasil resync tempat", data))
+}
+
+// Format bbox=minLng,minLat,maxLng,maxLat (sama seperti GeoJSON)
+func parseBBox(raw string) (*model.BBox, error) {
+	parts := strings.Split(raw, ",")
+	if len(parts) != 4 {
+		return nil, errors.New("format bbox harus minLng,minLat,maxLng,maxLat")
+	}
+	var v [4]float64
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return nil, errors.New("nilai bbox harus berupa angka")
+		}
+		v[i] = f
+	}
+	bbox := &model.BBox{MinLng: v[0], MinLat: v[1], MaxLng: v[2], MaxLat: v[3]}
+	if bbox.MinLat > bbox.MaxLat || bbox.MinLng > bbox.MaxLng ||
+		bbox.MinLat < -90 || bbox.MaxLat > 90 || bbox.MinLng < -180 || bbox.MaxLng > 180 {
+		return nil, errors.New("nilai bbox tidak valid")
+	}
+	return bbox, nil
+}
+
 // Proxy
 func (h *MapsHandler) ProxyPhotoHandler(c *gin.Context) {
 	photoRef := c.Query("ref")
@@ -225,24 +691,73 @@ func (h *MapsHandler) ProxyPhotoHandler(c *gin.Context) {
 		return
 	}
 
-	photoURL, err := h.gmaps.PhotoReference(photoRef)
+	maxWidth, err := parsePhotoSize(c.Query("maxwidth"))
 	if err != nil {
-		c.JSON(utils.ConverResponse(err), utils.ResponseHandler(constant.StatusFail, err.Error(), nil))
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+	maxHeight, err := parsePhotoSize(c.Query("maxheight"))
+	if err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+	// hanya URL dari response list/detail yang boleh dipakai, ref & ukuran ikut ditandatangani
+	if err := h.photo.VerifyPhoto(photoRef, maxWidth, maxHeight, c.Query("exp"), c.Query("sig")); err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
 		return
 	}
+	if maxWidth == 0 && maxHeight == 0 {
+		maxWidth = constant.PhotoDefaultWidth
+	}
 
-	client := &http.Client{Timeout: 10 * time.Second} // timeout
-	resp, err := client.Get(photoURL)
-	if err != nil || resp.StatusCode != http.StatusOK {
+	// foto untuk ref + ukuran yang sama tidak berubah, jadi 304 bisa dijawab tanpa ambil foto
+	etag := `"` + gmaps.PhotoETag(photoRef, maxWidth, maxHeight) + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d, immutable", constant.PhotoCacheMaxAge))
+	if etagMatch(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	photo, err := h.gmaps.GetPhoto(c.Request.Context(), photoRef, maxWidth, maxHeight)
+	if err != nil {
+		c.Header("Cache-Control", "no-store")
+		if status := utils.ConverResponse(err); status != http.StatusInternalServerError {
+			c.JSON(status, utils.ErrorResponseHandler(err))
+			return
+		}
 		c.JSON(http.StatusBadGateway, utils.ResponseHandler(constant.StatusFail, "error terjadi kesalahan mengambil gambar", nil))
 		return
 	}
-	defer resp.Body.Close()
 
-	c.Header("Content-Type", resp.Header.Get("Content-Type"))
-	c.Status(http.StatusOK)
+	c.Data(http.StatusOK, photo.ContentType, photo.Data)
+}
+
+// Kosong berarti tidak dikirim, selain itu harus salah satu dari constant.PhotoSizes
+func parsePhotoSize(val string) (int, error) {
+	if val == "" {
+		return 0, nil
+	}
+	size, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, utils.ErrPhotoSize
+	}
+	for _, v := range constant.PhotoSizes {
+		if v == size {
+			return size, nil
+		}
+	}
+	return 0, utils.ErrPhotoSize
+}
 
-	io.Copy(c.Writer, resp.Body)
+func etagMatch(header, etag string) bool {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+		if v == etag || v == "*" {
+			return true
+		}
+	}
+	return false
 }
 
 func (h *MapsHandler) RouteDestination(c *gin.Context) {
@@ -265,33 +780,15 @@ func (h *MapsHandler) RouteDestination(c *gin.Context) {
 
 	ctx := c.Request.Context()
 
-	var req model.RequestRouteMaps
+	var req model.RequestRouteOptions
 	if err := c.Bind(&req); err != nil {
 		c.JSON(http.StatusUnprocessableEntity, utils.ResponseHandler(constant.StatusFail, "error memproses data", nil))
 		return
 	}
 
-	modelReq := model.RequestRouteMaps{
-		Origin: model.Waypoint{
-			Location: model.LocationReq{
-				LatLng: model.LatLng{
-					Latitude:  req.Origin.Location.LatLng.Latitude,
-					Longitude: req.Origin.Location.LatLng.Longitude,
-				},
-			},
-		}, Destination: model.Waypoint{
-			Location: model.LocationReq{
-				LatLng: model.LatLng{
-					Latitude:  req.Destination.Location.LatLng.Latitude,
-					Longitude: req.Destination.Location.LatLng.Longitude,
-				},
-			},
-		},
-		TravelMode: req.TravelMode,
-	}
-	data, err := h.us.RouteDestination(ctx, modelReq, placeId)
+	data, err := h.us.RouteDestination(ctx, req, placeId)
 	if err != nil {
-		c.JSON(utils.ConverResponse(err), utils.ResponseHandler(constant.StatusFail, err.Error(), nil))
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
 		return
 	}
 
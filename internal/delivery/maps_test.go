@@ -0,0 +1,101 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"proyek1/config"
+	"proyek1/internal/delivery/middleware"
+	"proyek1/internal/model"
+	"proyek1/utils"
+	"proyek1/utils/gmaps"
+	"proyek1/utils/places"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type activeSessions struct{}
+
+func (activeSessions) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	return true, nil
+}
+
+// Router /maps dengan provider Google yang membaca fixtures/gmaps, tanpa API key dan jaringan
+func newSearchRouter(t *testing.T) (*gin.Engine, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	gm := gmaps.NewMail(config.GMAPS{
+		GMAPS_MODE:        gmaps.ModeFixture,
+		GMAPS_FIXTURE_DIR: "../../fixtures/gmaps",
+	}, log)
+	jwt := utils.NewJWT("test-secret", time.Minute)
+	h := NewMapsHandler(jwt, &gm, places.NewGoogle(&gm), nil, nil)
+
+	r := gin.New()
+	private := r.Group("/")
+	private.Use(middleware.NewAuth(jwt, activeSessions{}))
+	private.GET("/maps", h.GmapsSearchbyObject)
+
+	token, err := jwt.GenerateAccessToken(&model.User{ID: "user-1", Email: "user@mail.com", Role: "users"}, "sesi-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, token
+}
+
+func TestGmapsSearchbyObjectFixture(t *testing.T) {
+	r, token := newSearchRouter(t)
+
+	tests := []struct {
+		name       string
+		query      string
+		auth       bool
+		wantStatus int
+		wantPlace  string
+	}{
+		{name: "cari monas", query: "?query=monas", auth: true, wantStatus: http.StatusCreated, wantPlace: "ChIJLbFk59L1aS4RyLzp4OHWKj0"},
+		{name: "dengan lokasi dan bahasa", query: "?query=monas&lat=-6.2&lng=106.8&radius=5000&language=en", auth: true, wantStatus: http.StatusCreated, wantPlace: "ChIJLbFk59L1aS4RyLzp4OHWKj0"},
+		{name: "query kosong", query: "", auth: true, wantStatus: http.StatusBadRequest},
+		{name: "radius tanpa lokasi", query: "?query=monas&radius=5000", auth: true, wantStatus: http.StatusBadRequest},
+		{name: "tanpa token", query: "?query=monas", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/maps"+tt.query, nil)
+			if tt.auth {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, mau %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantPlace == "" {
+				return
+			}
+			var res struct {
+				Status string     `json:"status"`
+				Data   model.Maps `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+				t.Fatalf("response bukan JSON: %v", err)
+			}
+			if res.Data.PlaceID != tt.wantPlace || res.Data.Name != "Monumen Nasional" || res.Data.Source != gmaps.Source {
+				t.Errorf("data = %+v", res.Data)
+			}
+			if res.Data.Geometry.Lat != "-6.175392" || res.Data.Geometry.Lng != "106.827153" {
+				t.Errorf("geometry = %+v", res.Data.Geometry)
+			}
+		})
+	}
+}
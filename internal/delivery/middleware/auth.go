@@ -1,15 +1,22 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"proyek1/internal/model"
+	"proyek1/utils"
 	jwt "proyek1/utils"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-func NewAuth(jwt jwt.JWTInterface) gin.HandlerFunc {
+// Status sesi dicek ke database setiap request supaya logout langsung berlaku
+type SessionChecker interface {
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
+}
+
+func NewAuth(jwt jwt.JWTInterface, sessions SessionChecker) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
 		if authHeader == "" {
@@ -29,7 +36,23 @@ func NewAuth(jwt jwt.JWTInterface) gin.HandlerFunc {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"errors": err.Error()})
 			return
 		}
+
+		// token login lama (sebelum ada sesi) tidak punya sid, harus login ulang
+		if userData.SessionID == "" {
+			ctx.AbortWithStatusJSON(utils.ConverResponse(utils.ErrSessionRevoked), utils.ErrorResponseHandler(utils.ErrSessionRevoked))
+			return
+		}
+		active, err := sessions.IsSessionActive(ctx.Request.Context(), userData.SessionID)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": utils.InternalServer})
+			return
+		}
+		if !active {
+			ctx.AbortWithStatusJSON(utils.ConverResponse(utils.ErrSessionRevoked), utils.ErrorResponseHandler(utils.ErrSessionRevoked))
+			return
+		}
 		ctx.Set("auth", userData)
+		ctx.Request = ctx.Request.WithContext(utils.WithUser(ctx.Request.Context(), userData))
 		ctx.Next()
 	}
 }
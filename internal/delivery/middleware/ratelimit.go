@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"math"
+	"proyek1/constant"
+	"proyek1/utils"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Token bucket per IP: perMinute request per menit, boleh lonjakan sampai burst.
+// IP yang sudah lama tidak request dibersihkan berkala
+func NewRateLimit(perMinute, burst int) gin.HandlerFunc {
+	if perMinute <= 0 {
+		perMinute = constant.PhotoRateLimit
+	}
+	if burst <= 0 {
+		burst = constant.PhotoRateBurst
+	}
+	rate := float64(perMinute) / 60 // token per detik
+
+	var mu sync.Mutex
+	buckets := make(map[string]*bucket)
+	lastCleanup := time.Now()
+
+	return func(ctx *gin.Context) {
+		ip := ctx.ClientIP() // X-Forwarded-For hanya dipakai dari TRUSTED_PROXIES, lihat main.go
+		now := time.Now()
+
+		mu.Lock()
+		if now.Sub(lastCleanup) > 10*time.Minute {
+			for k, b := range buckets {
+				if now.Sub(b.last) > 10*time.Minute {
+					delete(buckets, k)
+				}
+			}
+			lastCleanup = now
+		}
+
+		b, ok := buckets[ip]
+		if !ok {
+			b = &bucket{tokens: float64(burst), last: now}
+			buckets[ip] = b
+		}
+		b.tokens = math.Min(float64(burst), b.tokens+now.Sub(b.last).Seconds()*rate)
+		b.last = now
+
+		allowed := b.tokens >= 1
+		var wait float64
+		if allowed {
+			b.tokens--
+		} else {
+			wait = (1 - b.tokens) / rate
+		}
+		mu.Unlock()
+
+		if !allowed {
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait))))
+			ctx.AbortWithStatusJSON(utils.ConverResponse(utils.ErrTooManyRequest), utils.ResponseHandler(constant.StatusFail, utils.ErrTooManyRequest.Error(), nil))
+			return
+		}
+		ctx.Next()
+	}
+}
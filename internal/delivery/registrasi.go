@@ -10,6 +10,7 @@ import (
 	"proyek1/utils"
 	crypto "proyek1/utils"
 	jwt "proyek1/utils"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -25,8 +26,16 @@ type RegisterUsecaseInterface interface {
 
 	ForgotPassword(ctx context.Context, req *model.Otp) error
 	OtpVerify(ctx context.Context, req *model.Otp) (*model.Otp, error)
-	ResetPassword(ctx context.Context, req *model.User) error
-	ActivateAcount(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token string, req *model.User) error
+	ActivateAcount(ctx context.Context, token string) error
+	ChangeEmail(ctx context.Context, userID, email string) error
+	ConfirmChangeEmail(ctx context.Context, token string) error
+
+	RefreshToken(ctx context.Context, refreshToken string) (*model.Login, error)
+	Logout(ctx context.Context, userID, sessionID string) error
+	LogoutAll(ctx context.Context, userID string) (int64, error)
+	GetSessions(ctx context.Context, userID, currentSessionID string) ([]model.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) error
 }
 
 type RegisterHandlerInterface interface {
@@ -39,6 +48,13 @@ type RegisterHandlerInterface interface {
 	OtpVerify(c *gin.Context)
 	ResetPassword(c *gin.Context)
 	ActivateAcount(c *gin.Context)
+	ChangeEmail(c *gin.Context)
+	ConfirmChangeEmail(c *gin.Context)
+	RefreshToken(c *gin.Context)
+	Logout(c *gin.Context)
+	LogoutAll(c *gin.Context)
+	GetSessions(c *gin.Context)
+	RevokeSession(c *gin.Context)
 }
 
 type UserHandler struct {
@@ -90,16 +106,109 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
+	data.UserAgent = c.Request.UserAgent()
+	data.IP = c.ClientIP()
+
 	ctx := c.Request.Context()
 	result, err := h.uc.Login(ctx, &data)
 	if err != nil {
 		c.JSON(utils.ConverResponse(err), utils.ResponseHandler(constant.StatusFail, err.Error(), nil))
 		return
 	}
-	res := map[string]string{
-		"token": result.Token,
+	c.JSON(http.StatusOK, utils.ResponseHandler(constant.StatusSuccess, "Login berhasil", tokenResponse(result)))
+}
+
+// Refresh token hanya bisa dipakai sekali, response berisi refresh token baru yang harus disimpan client
+func (h *UserHandler) RefreshToken(c *gin.Context) {
+	var data model.RefreshToken
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, utils.ResponseHandler(constant.StatusFail, "error memproses data", nil))
+		return
+	}
+
+	ctx := c.Request.Context()
+	result, err := h.uc.RefreshToken(ctx, data.RefreshToken)
+	if err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseHandler(constant.StatusSuccess, "Berhasil memperbarui token", tokenResponse(result)))
+}
+
+// Cabut sesi dari token yang dipakai, access token dan refresh token sesi ini langsung tidak berlaku
+func (h *UserHandler) Logout(c *gin.Context) {
+	dataToken, ok := middleware.GetUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "Unauthorize", nil))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.uc.Logout(ctx, dataToken.ID, dataToken.SessionID); err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseHandler(constant.StatusSuccess, "Berhasil logout", nil))
+}
+
+// Cabut semua sesi user di semua perangkat, termasuk sesi yang sedang dipakai
+func (h *UserHandler) LogoutAll(c *gin.Context) {
+	dataToken, ok := middleware.GetUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "Unauthorize", nil))
+		return
+	}
+
+	ctx := c.Request.Context()
+	total, err := h.uc.LogoutAll(ctx, dataToken.ID)
+	if err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseHandler(constant.StatusSuccess, "Berhasil logout dari semua perangkat", map[string]int64{
+		"revoked": total,
+	}))
+}
+
+// Daftar sesi login yang masih aktif, sesi dari token ini ditandai current
+func (h *UserHandler) GetSessions(c *gin.Context) {
+	dataToken, ok := middleware.GetUser(c)
+	if !ok || dataToken.SessionID == "" {
+		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "Unauthorize", nil))
+		return
+	}
+
+	ctx := c.Request.Context()
+	data, err := h.uc.GetSessions(ctx, dataToken.ID, dataToken.SessionID)
+	if err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseHandler(constant.StatusSuccess, "Berhasil mendapatkan data", data))
+}
+
+// Cabut satu sesi, termasuk sesi yang sedang dipakai (sama dengan logout)
+func (h *UserHandler) RevokeSession(c *gin.Context) {
+	dataToken, ok := middleware.GetUser(c)
+	if !ok || dataToken.SessionID == "" {
+		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "Unauthorize", nil))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.uc.RevokeSession(ctx, dataToken.ID, c.Param("id")); err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseHandler(constant.StatusSuccess, "Berhasil mencabut sesi", nil))
+}
+
+func tokenResponse(l *model.Login) map[string]interface{} {
+	return map[string]interface{}{
+		"token":         l.Token,
+		"refresh_token": l.RefreshToken,
+		"expires_in":    l.ExpiresIn,
 	}
-	c.JSON(http.StatusOK, utils.ResponseHandler(constant.StatusSuccess, "Login berhasil", res))
 }
 
 func (h *UserHandler) Profile(c *gin.Context) {
@@ -242,18 +351,9 @@ func (h *UserHandler) OtpVerify(c *gin.Context) {
 	c.JSON(http.StatusCreated, utils.ResponseHandler(constant.StatusSuccess, "Otp telah dikirim ke email, cek spam jika tidak terlihat di beranda email", res))
 }
 
+// Token reset dari /otp-verify dikirim di body "token", header Bearer masih diterima untuk client lama
 func (h *UserHandler) ResetPassword(c *gin.Context) {
 	ctx := c.Request.Context()
-	dataToken, ok := middleware.GetUser(c)
-	if !ok {
-		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "Unauthorize", nil))
-		return
-	}
-
-	if !crypto.IsForgot(dataToken.Role) {
-		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "error unknown token data", nil))
-		return
-	}
 
 	var data model.User
 	if err := c.Bind(&data); err != nil {
@@ -261,15 +361,19 @@ func (h *UserHandler) ResetPassword(c *gin.Context) {
 		return
 	}
 
+	token := data.Token
+	if token == "" {
+		token = strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	}
+
 	modelData := model.User{
-		Email:           dataToken.Email,
 		Password:        data.Password,
 		ConfirmPassword: data.ConfirmPassword,
 	}
 
-	err := h.uc.ResetPassword(ctx, &modelData)
+	err := h.uc.ResetPassword(ctx, token, &modelData)
 	if err != nil {
-		c.JSON(utils.ConverResponse(err), utils.ResponseHandler(constant.StatusFail, err.Error(), nil))
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
 		return
 	}
 
@@ -283,21 +387,48 @@ func (h *UserHandler) ActivateAcount(c *gin.Context) {
 		return
 	}
 
-	userData, err := h.jwt.VerifyToken(token)
+	ctx := c.Request.Context()
+	err := h.uc.ActivateAcount(ctx, token)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "invalid token", nil))
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
 		return
 	}
 
-	if !crypto.IsUser(userData.Role) {
-		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "error unknown token data", nil))
+	c.Redirect(http.StatusSeeOther, constant.VercelRoute) // 303
+}
+
+func (h *UserHandler) ChangeEmail(c *gin.Context) {
+	dataToken, ok := middleware.GetUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "Unauthorize", nil))
+		return
+	}
+
+	var data model.ChangeEmail
+	if err := c.Bind(&data); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, utils.ResponseHandler(constant.StatusFail, "error memproses data", nil))
 		return
 	}
 
 	ctx := c.Request.Context()
-	err = h.uc.ActivateAcount(ctx, userData.Email)
-	if err != nil {
-		c.JSON(utils.ConverResponse(err), utils.ResponseHandler(constant.StatusFail, err.Error(), nil))
+	if err := h.uc.ChangeEmail(ctx, dataToken.ID, data.Email); err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseHandler(constant.StatusSuccess, "Link konfirmasi telah dikirim ke email baru, cek spam jika tidak terlihat di beranda email", nil))
+}
+
+// Link dari email konfirmasi ganti email
+func (h *UserHandler) ConfirmChangeEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, utils.ResponseHandler(constant.StatusFail, "Unauthorize", nil))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.uc.ConfirmChangeEmail(ctx, token); err != nil {
+		c.JSON(utils.ConverResponse(err), utils.ErrorResponseHandler(err))
 		return
 	}
 
@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"proyek1/config"
 	"proyek1/internal/delivery"
 	"proyek1/internal/delivery/middleware"
 	"proyek1/utils"
@@ -9,10 +10,13 @@ import (
 )
 
 type RouteConfig struct {
-	App            *gin.Engine
-	UserController *delivery.UserHandler
-	MapsController *delivery.MapsHandler
-	JWT            utils.JWTInterface
+	App             *gin.Engine
+	UserController  *delivery.UserHandler
+	MapsController  *delivery.MapsHandler
+	UsageController *delivery.GmapsUsageHandler
+	JWT             utils.JWTInterface
+	Sessions        middleware.SessionChecker
+	Cfg             *config.Config
 }
 
 func (c *RouteConfig) Setup() {
@@ -27,25 +31,43 @@ func (c *RouteConfig) SetupUserRoute() {
 	c.App.POST("/forgot-password", c.UserController.ForgotPassword)
 	c.App.POST("/otp-verify", c.UserController.OtpVerify)
 	c.App.GET("/active", c.UserController.ActivateAcount)
+	c.App.PUT("/reset-password", c.UserController.ResetPassword)
+	c.App.GET("/confirm-email", c.UserController.ConfirmChangeEmail)
+	c.App.POST("/token/refresh", c.UserController.RefreshToken)
 
 	private := c.App.Group("/")
-	private.Use(middleware.NewAuth(c.JWT))
+	private.Use(middleware.NewAuth(c.JWT, c.Sessions))
 	private.GET("/profile", c.UserController.Profile)
 	private.PUT("/profile", c.UserController.EditProfile)
-	private.PUT("/reset-password", c.UserController.ResetPassword)
+	private.POST("/change-email", c.UserController.ChangeEmail)
+	private.POST("/logout", c.UserController.Logout)
+	private.POST("/logout-all", c.UserController.LogoutAll)
+	private.GET("/sessions", c.UserController.GetSessions)
+	private.DELETE("/sessions/:id", c.UserController.RevokeSession)
 }
 
 func (c *RouteConfig) SetupMapsRoute() {
-	c.App.GET("/photo", c.MapsController.ProxyPhotoHandler) // app use global, nanti kena semua
+	// publik tapi URL harus ditandatangani, dan dibatasi per IP
+	photoLimit := middleware.NewRateLimit(c.Cfg.Photo.PHOTO_RATE_LIMIT, c.Cfg.Photo.PHOTO_RATE_BURST)
+	c.App.GET("/photo", photoLimit, c.MapsController.ProxyPhotoHandler) // app use global, nanti kena semua
 
 	private := c.App.Group("/")
-	private.Use(middleware.NewAuth(c.JWT))
+	private.Use(middleware.NewAuth(c.JWT, c.Sessions))
 	private.GET("/tempat-par", c.MapsController.GetTempatPagination)
+	private.GET("/tempat-par.geojson", c.MapsController.GetTempatGeoJSON)
+	private.GET("/tempat-par/bbox", c.MapsController.GetTempatViewport)
 	private.GET("/tempat-par/:id", c.MapsController.GetDetailTempat)
+	private.GET("/regions", c.MapsController.GetRegions)
 
 	private.GET("/maps", c.MapsController.GmapsSearchbyObject)
 	private.GET("/maps-list", c.MapsController.GmapsSearchbyList)
+	private.GET("/maps/autocomplete", c.MapsController.Autocomplete)
 	private.GET("/place/:id", c.MapsController.GmapsSearchbyPlaceID)
+	private.GET("/geocode", c.MapsController.Geocode)
+	private.GET("/reverse-geocode", c.MapsController.ReverseGeocode)
 	private.POST("/place/:id", c.MapsController.InsertData)
 	private.POST("/route-maps/:id", c.MapsController.RouteDestination)
+
+	private.GET("/admin/gmaps-usage", c.UsageController.GetGmapsUsage)
+	private.POST("/admin/tempat-par/resync", c.MapsController.ResyncTempat)
 }
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+type GmapsUsage struct {
+	Day      time.Time
+	Endpoint string
+	Calls    int
+}
+
+type GmapsUsageUser struct {
+	UserID   string
+	Username string
+	Calls    int
+}
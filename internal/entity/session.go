@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+type Session struct {
+	ID         string
+	UserID     string
+	DeviceName string
+	UserAgent  string
+	IP         string
+	ExpiresAt  time.Time
+	LastUsedAt time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// Refresh token beserta status sesinya
+type RefreshToken struct {
+	TokenHash string
+	SessionID string
+	UserID    string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+
+	SessionExpiresAt time.Time
+	SessionRevokedAt *time.Time
+}
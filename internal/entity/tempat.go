@@ -7,9 +7,15 @@ type Tempat struct {
 	Name           string
 	Latitude       float64
 	Longtitude     float64
+	Geohash        string
 	Address        string
 	Icon           string
 	BusinessStatus string
+	Source         string
+	ProvinceCode   string
+	Province       string
+	Regency        string
+	District       string
 	Reviews        []Review
 	Photos         []Photo
 	OpeningHours   []Hour
@@ -74,3 +80,56 @@ type Type struct {
 type MasterCategory struct {
 	Code string `json:"code"`
 }
+
+// ==========================================================================================================================
+// Filter & GeoJSON
+type FilterTempat struct {
+	Name         string
+	BBox         *BBox
+	ProvinceCode string
+	Regency      string
+	District     string
+	// Tanpa ini tempat CLOSED_PERMANENTLY dan NOT_FOUND tidak ikut
+	IncludeClosed bool
+}
+
+type BBox struct {
+	MinLat float64
+	MinLng float64
+	MaxLat float64
+	MaxLng float64
+}
+
+type TempatGeo struct {
+	PlaceId        string
+	Name           string
+	Address        string
+	Latitude       float64
+	Longtitude     float64
+	BusinessStatus string
+	Rating         float64
+	Categories     []string
+}
+
+type TempatPoint struct {
+	ID             string
+	PlaceId        string
+	Name           string
+	Latitude       float64
+	Longtitude     float64
+	Rating         float64
+	BusinessStatus string
+}
+
+type TempatCluster struct {
+	Count      int
+	Latitude   float64
+	Longtitude float64
+	Top        TempatPoint
+}
+
+// Jumlah tempat per wilayah (provinsi/kabupaten-kota/kecamatan)
+type RegionCount struct {
+	Name  string
+	Count int
+}
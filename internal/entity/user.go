@@ -1,5 +1,7 @@
 package entity
 
+import "time"
+
 type User struct {
 	ID           string
 	Username     string
@@ -9,3 +11,12 @@ type User struct {
 	Role         string
 	IsActive     bool
 }
+
+// Token sekali pakai (aktivasi, reset password, ganti email)
+type UserToken struct {
+	TokenHash string
+	UserID    string
+	Purpose   string
+	Payload   string
+	ExpiresAt time.Time
+}
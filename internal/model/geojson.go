@@ -0,0 +1,66 @@
+package model
+
+type FilterTempat struct {
+	Name         string
+	BBox         *BBox
+	ProvinceCode string // kode provinsi dari utils/region
+	Regency      string
+	District     string
+	// Tanpa ini tempat yang tutup permanen atau sudah tidak ada di Google tidak ikut
+	IncludeClosed bool
+}
+
+// Urutan mengikuti GeoJSON: lng dulu baru lat
+type BBox struct {
+	MinLng float64 `json:"min_lng"`
+	MinLat float64 `json:"min_lat"`
+	MaxLng float64 `json:"max_lng"`
+	MaxLat float64 `json:"max_lat"`
+}
+
+type GeoJSONFeature struct {
+	Type       string            `json:"type"`
+	Geometry   GeoJSONGeometry   `json:"geometry"`
+	Properties GeoJSONProperties `json:"properties"`
+}
+
+type GeoJSONGeometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+type GeoJSONProperties struct {
+	PlaceID           string   `json:"place_id"`
+	Name              string   `json:"name"`
+	Address           string   `json:"address"`
+	Categories        []string `json:"categories"`
+	Rating            float64  `json:"rating"`
+	BusinessStatus    string   `json:"business_status"`
+	TemporarilyClosed bool     `json:"temporarily_closed"`
+}
+
+// Viewport map
+type ViewportTempat struct {
+	Mode      string            `json:"mode"` // "places" atau "clusters"
+	Total     int               `json:"total"`
+	Truncated bool              `json:"truncated"`
+	Places    []ViewportPlace   `json:"places,omitempty"`
+	Clusters  []ViewportCluster `json:"clusters,omitempty"`
+}
+
+type ViewportPlace struct {
+	ID                string  `json:"id"`
+	PlaceID           string  `json:"place_id"`
+	Name              string  `json:"name"`
+	Lat               float64 `json:"lat"`
+	Lng               float64 `json:"lng"`
+	Rating            float64 `json:"rating"`
+	TemporarilyClosed bool    `json:"temporarily_closed"`
+}
+
+type ViewportCluster struct {
+	Count    int           `json:"count"`
+	Lat      float64       `json:"lat"`
+	Lng      float64       `json:"lng"`
+	TopPlace ViewportPlace `json:"top_place"`
+}
@@ -0,0 +1,35 @@
+package model
+
+type GmapsUsageReport struct {
+	From          string               `json:"from"`
+	To            string               `json:"to"`
+	Currency      string               `json:"currency"`
+	TotalCalls    int                  `json:"total_calls"`
+	EstimatedCost float64              `json:"estimated_cost"`
+	Endpoints     []GmapsUsageEndpoint `json:"endpoints"`
+	Daily         []GmapsUsageDaily    `json:"daily"`
+	TopUsers      []GmapsUsageUser     `json:"top_users"`
+}
+
+type GmapsUsageEndpoint struct {
+	Endpoint      string  `json:"endpoint"`
+	Calls         int     `json:"calls"`
+	PricePer1000  float64 `json:"price_per_1000"`
+	EstimatedCost float64 `json:"estimated_cost"`
+	Today         int     `json:"today"`
+	ThisMonth     int     `json:"this_month"`
+	BudgetDaily   int     `json:"budget_daily"`   // 0 = tanpa batas
+	BudgetMonthly int     `json:"budget_monthly"` // 0 = tanpa batas
+}
+
+type GmapsUsageDaily struct {
+	Day           string  `json:"day"`
+	Calls         int     `json:"calls"`
+	EstimatedCost float64 `json:"estimated_cost"`
+}
+
+type GmapsUsageUser struct {
+	UserID   string `json:"user_id"` // kosong = request tanpa login
+	Username string `json:"username"`
+	Calls    int    `json:"calls"`
+}
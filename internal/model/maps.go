@@ -6,8 +6,10 @@ type GmapsAPIGetObject struct {
 		PlaceID  string   `json:"place_id"`
 		Name     string   `json:"name"`
 		Geometry Geometry `json:"geometry"`
+		Types    []string `json:"types"`
 	} `json:"candidates"`
-	Status string `json:"status"`
+	Status       string `json:"status"`
+	ErrorMessage string `json:"error_message"`
 }
 
 // List
@@ -16,27 +18,57 @@ type GmapsAPIGetTextSearch struct {
 	Place            []PlaceResult `json:"results"`
 	NextPageToken    string        `json:"next_page_token"`
 	Status           string        `json:"status"`
+	ErrorMessage     string        `json:"error_message"`
 }
 
 type GmapsAPIGetPlaceDetails struct {
-	Place  PlaceResult `json:"result"`
-	Status string      `json:"status"`
+	Place        PlaceResult `json:"result"`
+	Status       string      `json:"status"`
+	ErrorMessage string      `json:"error_message"`
+}
+
+type GmapsAPIGeocode struct {
+	Results []struct {
+		PlaceID           string             `json:"place_id"`
+		FormattedAddress  string             `json:"formatted_address"`
+		Geometry          Geometry           `json:"geometry"`
+		Types             []string           `json:"types"`
+		AddressComponents []AddressComponent `json:"address_components"`
+	} `json:"results"`
+	Status       string `json:"status"`
+	ErrorMessage string `json:"error_message"`
+}
+
+type GmapsAPIAutocomplete struct {
+	Predictions []struct {
+		PlaceID              string `json:"place_id"`
+		Description          string `json:"description"`
+		StructuredFormatting struct {
+			MainText      string `json:"main_text"`
+			SecondaryText string `json:"secondary_text"`
+		} `json:"structured_formatting"`
+		Types          []string `json:"types"`
+		DistanceMeters int      `json:"distance_meters"`
+	} `json:"predictions"`
+	Status       string `json:"status"`
+	ErrorMessage string `json:"error_message"`
 }
 
 //
 type PlaceResult struct {
-	PlaceID             string      `json:"place_id"`
-	Name                string      `json:"name"`
-	FormattedAddress    string      `json:"formatted_address"`
-	NavigasiURL         string      `json:"navigasi_url"`
-	Geometry            Geometry    `json:"geometry"`
-	Icon                string      `json:"icon"`
-	Rating              float64     `json:"rating"`
-	Reviews             []Review    `json:"reviews"`
-	RegularOpeningHours OpeningHour `json:"current_opening_hours"`
-	Photos              []Photo     `json:"photos"`
-	BusinessStatus      string      `json:"business_status"`
-	Types               []string    `json:"types"`
+	PlaceID             string             `json:"place_id"`
+	Name                string             `json:"name"`
+	FormattedAddress    string             `json:"formatted_address"`
+	NavigasiURL         string             `json:"navigasi_url"`
+	Geometry            Geometry           `json:"geometry"`
+	Icon                string             `json:"icon"`
+	Rating              float64            `json:"rating"`
+	Reviews             []Review           `json:"reviews"`
+	RegularOpeningHours OpeningHour        `json:"current_opening_hours"`
+	Photos              []Photo            `json:"photos"`
+	BusinessStatus      string             `json:"business_status"`
+	Types               []string           `json:"types"`
+	AddressComponents   []AddressComponent `json:"address_components"`
 }
 
 type Geometry struct {
@@ -67,6 +99,7 @@ type Photo struct {
 	WidthPx        int    `json:"width"`
 	HeightPx       int    `json:"height"`
 	PhotoReference string `json:"photo_reference"`
+	PhotoURL       string `json:"photo_url,omitempty"` // URL /photo yang sudah ditandatangani
 }
 
 type AuthorAttribution struct {
@@ -81,11 +114,53 @@ type Review struct {
 	Rating                         float64 `json:"rating"`
 }
 
+// Error dari Routes API (HTTP selain 200)
+type GmapsAPIError struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error"`
+}
+
 //
 type RequestRouteMaps struct {
-	Origin      Waypoint `json:"origin"`
-	Destination Waypoint `json:"destination"`
-	TravelMode  string   `json:"travelMode"`
+	Origin                   Waypoint        `json:"origin"`
+	Destination              Waypoint        `json:"destination"`
+	TravelMode               string          `json:"travelMode"`
+	RoutingPreference        string          `json:"routingPreference,omitempty"`
+	RouteModifiers           *RouteModifiers `json:"routeModifiers,omitempty"`
+	DepartureTime            string          `json:"departureTime,omitempty"`
+	ComputeAlternativeRoutes bool            `json:"computeAlternativeRoutes,omitempty"`
+	LanguageCode             string          `json:"languageCode,omitempty"`
+}
+
+type RouteModifiers struct {
+	AvoidTolls    bool `json:"avoidTolls,omitempty"`
+	AvoidHighways bool `json:"avoidHighways,omitempty"`
+	AvoidFerries  bool `json:"avoidFerries,omitempty"`
+}
+
+// Body dari client untuk POST /route-maps/:id, tujuan diambil dari tempat
+type RequestRouteOptions struct {
+	Origin        Waypoint `json:"origin"`
+	TravelMode    string   `json:"travelMode"`
+	AvoidTolls    bool     `json:"avoidTolls"`
+	AvoidHighways bool     `json:"avoidHighways"`
+	AvoidFerries  bool     `json:"avoidFerries"`
+	TrafficAware  bool     `json:"trafficAware"`
+	DepartureTime string   `json:"departureTime"` // RFC3339, contoh 2025-01-02T08:00:00+07:00
+	Alternatives  int      `json:"alternatives"`  // jumlah rute alternatif, 0 sampai 3
+}
+
+// Opsi pencarian dari query /maps dan /maps-list, semua boleh kosong
+type SearchOptions struct {
+	Location  *LatLng `json:"location,omitempty"`  // posisi user, dipakai sebagai location bias
+	Radius    int     `json:"radius,omitempty"`    // meter, hanya dipakai kalau Location ada
+	Region    string  `json:"region,omitempty"`    // kode negara, contoh "id"
+	Type      string  `json:"type,omitempty"`      // tipe tempat Google, contoh "tourist_attraction"
+	Language  string  `json:"language,omitempty"`  // "id" atau "en"
+	PageToken string  `json:"pageToken,omitempty"` // token dari response sebelumnya, hanya untuk list
 }
 
 type Waypoint struct {
@@ -102,11 +177,120 @@ type LatLng struct {
 }
 
 type ResponseRouteMaps struct {
-	Routes []struct {
-		Distance int    `json:"distanceMeters"`
-		Duration string `json:"duration"`
-		Polyline struct {
-			EncodePolyline string `json:"encodedPolyline"`
-		} `json:"polyline"`
-	} `json:"routes"`
+	Routes   []Route `json:"routes"`
+	Provider string  `json:"provider"` // google, osrm atau straight
+}
+
+// Field distanceText, durationText dan coordinates diisi sendiri setelah response Routes API di-parse
+type Route struct {
+	Distance     int        `json:"distanceMeters"`
+	Duration     string     `json:"duration"`
+	DistanceText string     `json:"distanceText"`
+	DurationText string     `json:"durationText"`
+	Polyline     Polyline   `json:"polyline"`
+	Coordinates  []LatLng   `json:"coordinates"`
+	Legs         []RouteLeg `json:"legs"`
+	// Hanya terisi untuk TRANSIT: urutan jalan kaki dan naik kendaraan umum
+	TransitSteps []TransitSegment `json:"transitSteps,omitempty"`
+}
+
+type Polyline struct {
+	EncodePolyline string `json:"encodedPolyline"`
+}
+
+type RouteLeg struct {
+	Distance      int         `json:"distanceMeters"`
+	Duration      string      `json:"duration"`
+	DistanceText  string      `json:"distanceText"`
+	DurationText  string      `json:"durationText"`
+	StartLocation LocationReq `json:"startLocation"`
+	EndLocation   LocationReq `json:"endLocation"`
+	Steps         []RouteStep `json:"steps"`
+}
+
+type RouteStep struct {
+	Distance              int                   `json:"distanceMeters"`
+	Duration              string                `json:"staticDuration"`
+	DistanceText          string                `json:"distanceText"`
+	DurationText          string                `json:"durationText"`
+	Polyline              Polyline              `json:"polyline"`
+	Coordinates           []LatLng              `json:"coordinates"`
+	NavigationInstruction NavigationInstruction `json:"navigationInstruction"`
+	TravelMode            string                `json:"travelMode"`
+	TransitDetails        *TransitDetails       `json:"transitDetails,omitempty"`
+}
+
+type NavigationInstruction struct {
+	Maneuver     string `json:"maneuver"`
+	Instructions string `json:"instructions"`
+}
+
+// Transit Routes API
+type TransitDetails struct {
+	StopDetails     TransitStopDetails     `json:"stopDetails"`
+	LocalizedValues TransitLocalizedValues `json:"localizedValues"`
+	Headsign        string                 `json:"headsign"`
+	StopCount       int                    `json:"stopCount"`
+	TransitLine     TransitLine            `json:"transitLine"`
+}
+
+type TransitStopDetails struct {
+	ArrivalStop   TransitStop `json:"arrivalStop"`
+	ArrivalTime   string      `json:"arrivalTime"`
+	DepartureStop TransitStop `json:"departureStop"`
+	DepartureTime string      `json:"departureTime"`
+}
+
+type TransitStop struct {
+	Name     string      `json:"name"`
+	Location LocationReq `json:"location"`
+}
+
+type TransitLocalizedValues struct {
+	ArrivalTime   LocalizedTime `json:"arrivalTime"`
+	DepartureTime LocalizedTime `json:"departureTime"`
+}
+
+type LocalizedTime struct {
+	Time struct {
+		Text string `json:"text"`
+	} `json:"time"`
+	TimeZone string `json:"timeZone"`
+}
+
+type TransitLine struct {
+	Agencies []struct {
+		Name string `json:"name"`
+	} `json:"agencies"`
+	Name      string `json:"name"`
+	NameShort string `json:"nameShort"`
+	Color     string `json:"color"`
+	TextColor string `json:"textColor"`
+	Vehicle   struct {
+		Name struct {
+			Text string `json:"text"`
+		} `json:"name"`
+		Type string `json:"type"`
+	} `json:"vehicle"`
+}
+
+// Ringkasan satu segmen perjalanan TRANSIT, langkah jalan kaki berurutan digabung jadi satu segmen
+type TransitSegment struct {
+	Type          string `json:"type"` // WALK atau TRANSIT
+	Distance      int    `json:"distanceMeters"`
+	Duration      string `json:"duration"`
+	DistanceText  string `json:"distanceText"`
+	DurationText  string `json:"durationText"`
+	LineName      string `json:"lineName,omitempty"`
+	LineShortName string `json:"lineShortName,omitempty"`
+	LineColor     string `json:"lineColor,omitempty"`
+	VehicleType   string `json:"vehicleType,omitempty"`
+	VehicleName   string `json:"vehicleName,omitempty"`
+	Agency        string `json:"agency,omitempty"`
+	Headsign      string `json:"headsign,omitempty"`
+	DepartureStop string `json:"departureStop,omitempty"`
+	ArrivalStop   string `json:"arrivalStop,omitempty"`
+	DepartureTime string `json:"departureTime,omitempty"`
+	ArrivalTime   string `json:"arrivalTime,omitempty"`
+	StopCount     int    `json:"stopCount,omitempty"`
 }
@@ -7,9 +7,20 @@ type GmapsResponse struct {
 }
 
 type Maps struct {
-	PlaceID  string       `json:"place_id"`
-	Name     string       `json:"name"`
-	Geometry LocationResp `json:"geometry"`
+	PlaceID          string       `json:"place_id"`
+	Name             string       `json:"name"`
+	Geometry         LocationResp `json:"geometry"`
+	FormattedAddress string       `json:"formatted_address,omitempty"`
+	Rating           float64      `json:"rating,omitempty"`
+	Types            []string     `json:"types,omitempty"`
+	Photo            *Photo       `json:"photo,omitempty"` // foto pertama
+	Source           string       `json:"source"`
+}
+
+// Response /maps-list, NextPageToken dikirim lagi sebagai query pageToken untuk halaman berikutnya
+type MapsList struct {
+	Results       []Maps `json:"results"`
+	NextPageToken string `json:"next_page_token,omitempty"`
 }
 
 type LocationResp struct {
@@ -18,16 +29,65 @@ type LocationResp struct {
 }
 
 type MapsGetByPlaceId struct {
-	PlaceID             string       `json:"place_id"`
-	Name                string       `json:"name"`
-	Geometry            LocationResp `json:"geometry"`
-	FormattedAddress    string       `json:"formatted_address"`
-	Icon                string       `json:"icon"`
-	NavigasiURL         string       `json:"navigasi_url"`
-	Rating              float64      `json:"rating"`
-	Reviews             []Review     `json:"reviews"`
-	RegularOpeningHours OpeningHour  `json:"current_opening_hours"`
-	Photos              []Photo      `json:"photos"`
-	BusinessStatus      string       `json:"business_status"`
-	Types               []string     `json:"types"`
+	PlaceID             string             `json:"place_id"`
+	Name                string             `json:"name"`
+	Geometry            LocationResp       `json:"geometry"`
+	FormattedAddress    string             `json:"formatted_address"`
+	Icon                string             `json:"icon"`
+	NavigasiURL         string             `json:"navigasi_url"`
+	Rating              float64            `json:"rating"`
+	Reviews             []Review           `json:"reviews"`
+	RegularOpeningHours OpeningHour        `json:"current_opening_hours"`
+	Photos              []Photo            `json:"photos"`
+	BusinessStatus      string             `json:"business_status"`
+	Types               []string           `json:"types"`
+	AddressComponents   []AddressComponent `json:"address_components,omitempty"`
+	Address             Address            `json:"address"`
+	Source              string             `json:"source"`
+}
+
+// Hasil /maps/autocomplete, Local true berarti tempat sudah ada di tempat_pariwisata
+// dan detailnya bisa dibuka dari /tempat-par/:id tanpa memanggil Google
+type Prediction struct {
+	PlaceID        string   `json:"place_id"`
+	Description    string   `json:"description"`
+	MainText       string   `json:"main_text"`
+	SecondaryText  string   `json:"secondary_text"`
+	Types          []string `json:"types"`
+	DistanceMeters int      `json:"distance_meters,omitempty"`
+	Local          bool     `json:"local"`
+	Source         string   `json:"source"`
+}
+
+type PhotoFile struct {
+	ContentType string
+	Data        []byte
+}
+
+// Geocoding
+type GeocodeResult struct {
+	PlaceID           string             `json:"place_id"`
+	FormattedAddress  string             `json:"formatted_address"`
+	Geometry          LocationResp       `json:"geometry"`
+	Types             []string           `json:"types"`
+	AddressComponents []AddressComponent `json:"address_components"`
+	Address           Address            `json:"address"`
+	Source            string             `json:"source"`
+}
+
+// Hasil urai address_components
+type Address struct {
+	Province    string `json:"province"`
+	City        string `json:"city"`     // kota atau kabupaten
+	District    string `json:"district"` // kecamatan
+	Village     string `json:"village"`  // kelurahan/desa
+	PostalCode  string `json:"postal_code"`
+	Country     string `json:"country"`
+	CountryCode string `json:"country_code"`
+}
+
+type AddressComponent struct {
+	LongName  string   `json:"long_name"`
+	ShortName string   `json:"short_name"`
+	Types     []string `json:"types"`
 }
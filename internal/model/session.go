@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// Sesi login yang masih aktif, Current = sesi dari token yang dipakai request ini
+type Session struct {
+	ID         string    `json:"id"`
+	DeviceName string    `json:"device_name"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	Current    bool      `json:"current"`
+}
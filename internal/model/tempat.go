@@ -1,16 +1,24 @@
 package model
 
 type GetAllTempat struct {
-	ID           string             `json:"id"`
-	PlaceId      string             `json:"place_id"`
-	Name         string             `json:"name"`
-	Address      string             `json:"address"`
-	Photos       []FotoTempatGetAll `json:"photos"`
-	OpeningHours []HourTempatGetAll `json:"opening_hours"`
+	ID           string `json:"id"`
+	PlaceId      string `json:"place_id"`
+	Name         string `json:"name"`
+	Address      string `json:"address"`
+	ProvinceCode string `json:"province_code"`
+	Province     string `json:"province"`
+	Regency      string `json:"regency"`
+	District     string `json:"district"`
+	// Tempat tutup sementara tetap tampil, client yang menandai
+	BusinessStatus    string             `json:"business_status"`
+	TemporarilyClosed bool               `json:"temporarily_closed"`
+	Photos            []FotoTempatGetAll `json:"photos"`
+	OpeningHours      []HourTempatGetAll `json:"opening_hours"`
 }
 
 type FotoTempatGetAll struct {
 	PhotoRefrences string `json:"photo_reference"`
+	PhotoURL       string `json:"photo_url"`
 	WidthPx        int    `json:"width_px"`
 	HeightPx       int    `json:"height_px"`
 }
@@ -35,6 +43,7 @@ type GetDetailTempat struct {
 	RegularOpeningHours DetailHour `json:"current_opening_hours"`
 	Photos              []Photo    `json:"photos"`
 	BusinessStatus      string     `json:"business_status"`
+	TemporarilyClosed   bool       `json:"temporarily_closed"`
 	Types               []Type     `json:"types"`
 }
 
@@ -46,3 +55,25 @@ type Type struct {
 	PlaceID      string `json:"place_id"`
 	CategoryCode string `json:"category_code"`
 }
+
+// Response /regions, Level = province, regency atau district
+type Regions struct {
+	Level   string        `json:"level"`
+	Regions []RegionCount `json:"regions"`
+}
+
+type RegionCount struct {
+	Code  string `json:"code,omitempty"` // hanya untuk provinsi
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Hasil resync satu tempat. NewPlaceID terisi kalau Google memindahkan place_id,
+// Hidden true kalau tempat sekarang tidak tampil di list (tutup permanen / tidak ditemukan)
+type ResyncTempat struct {
+	PlaceID        string `json:"place_id"`
+	NewPlaceID     string `json:"new_place_id,omitempty"`
+	BusinessStatus string `json:"business_status"`
+	Hidden         bool   `json:"hidden"`
+	Error          string `json:"error,omitempty"`
+}
@@ -10,6 +10,7 @@ type User struct {
 	Role            string `json:"role"`
 	Token           string `json:"token"`
 	IsActive        bool   `json:"is_active"`
+	SessionID       string `json:"-"` // claim sid dari access token
 }
 
 type Register struct {
@@ -20,9 +21,18 @@ type Register struct {
 }
 
 type Login struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
-	Token    string `json:"token"`
+	Email        string `json:"email"`
+	Password     string `json:"password"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`            // detik sampai access token habis
+	DeviceName   string `json:"device_name,omitempty"` // opsional dari aplikasi, kosong = ditebak dari user agent
+	UserAgent    string `json:"-"`
+	IP           string `json:"-"`
+}
+
+type RefreshToken struct {
+	RefreshToken string `json:"refresh_token"`
 }
 
 type Auth struct {
@@ -43,3 +53,7 @@ type EditProfile struct {
 	PhotoProfile string `json:"photo_profile"`
 	Password     string `json:"password"`
 }
+
+type ChangeEmail struct {
+	Email string `json:"email"`
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type GmapsCacheRepo struct {
+	db  *sql.DB
+	log *logrus.Logger
+}
+
+func NewGmapsCacheRepository(db *sql.DB, log *logrus.Logger) *GmapsCacheRepo {
+	return &GmapsCacheRepo{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *GmapsCacheRepo) GetCache(ctx context.Context, key string, allowStale bool) ([]byte, time.Time, error) {
+	var value []byte
+	var expiresAt time.Time
+	query := `SELECT value, expires_at FROM gmaps_cache WHERE key = $1 AND ($2 OR expires_at > NOW())`
+	err := r.db.QueryRowContext(ctx, query, key, allowStale).Scan(&value, &expiresAt)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return value, expiresAt, nil
+}
+
+func (r *GmapsCacheRepo) SetCache(ctx context.Context, key, kind string, value []byte, expiresAt time.Time) error {
+	query := `
+		INSERT INTO gmaps_cache (key, kind, value, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at, created_at = NOW()
+	`
+	// value dikirim sebagai string, kalau []byte lib/pq mengirimnya sebagai bytea
+	_, err := r.db.ExecContext(ctx, query, key, kind, string(value), expiresAt)
+	return err
+}
+
+// Row expired disimpan 7 hari lagi untuk dipakai saat budget gmaps habis
+func (r *GmapsCacheRepo) PurgeCache(ctx context.Context) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM gmaps_cache WHERE expires_at <= NOW() - INTERVAL '7 days'`)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"proyek1/internal/entity"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Tanggal dikirim sebagai string supaya tidak bergeser karena timezone server database
+const dateFormat = "2006-01-02"
+
+type GmapsUsageRepo struct {
+	db  *sql.DB
+	log *logrus.Logger
+}
+
+func NewGmapsUsageRepository(db *sql.DB, log *logrus.Logger) *GmapsUsageRepo {
+	return &GmapsUsageRepo{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *GmapsUsageRepo) AddUsage(ctx context.Context, day time.Time, endpoint, userID string, calls int) error {
+	query := `
+		INSERT INTO gmaps_usage (day, endpoint, user_id, calls)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (day, endpoint, user_id) DO UPDATE SET calls = gmaps_usage.calls + EXCLUDED.calls, updated_at = NOW()
+	`
+	_, err := r.db.ExecContext(ctx, query, day.Format(dateFormat), endpoint, userID, calls)
+	return err
+}
+
+// Total panggilan per endpoint, from dan to inklusif
+func (r *GmapsUsageRepo) GetUsageTotals(ctx context.Context, from, to time.Time) (map[string]int, error) {
+	query := `SELECT endpoint, SUM(calls) FROM gmaps_usage WHERE day BETWEEN $1 AND $2 GROUP BY endpoint`
+	rows, err := r.db.QueryContext(ctx, query, from.Format(dateFormat), to.Format(dateFormat))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make(map[string]int)
+	for rows.Next() {
+		var endpoint string
+		var calls int
+		if err := rows.Scan(&endpoint, &calls); err != nil {
+			return nil, err
+		}
+		res[endpoint] = calls
+	}
+	return res, rows.Err()
+}
+
+func (r *GmapsUsageRepo) GetUsageDaily(ctx context.Context, from, to time.Time) ([]entity.GmapsUsage, error) {
+	query := `
+		SELECT day, endpoint, SUM(calls)
+		FROM gmaps_usage
+		WHERE day BETWEEN $1 AND $2
+		GROUP BY day, endpoint
+		ORDER BY day, endpoint
+	`
+	rows, err := r.db.QueryContext(ctx, query, from.Format(dateFormat), to.Format(dateFormat))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []entity.GmapsUsage
+	for rows.Next() {
+		var u entity.GmapsUsage
+		if err := rows.Scan(&u.Day, &u.Endpoint, &u.Calls); err != nil {
+			return nil, err
+		}
+		res = append(res, u)
+	}
+	return res, rows.Err()
+}
+
+func (r *GmapsUsageRepo) GetUsageUsers(ctx context.Context, from, to time.Time, limit int) ([]entity.GmapsUsageUser, error) {
+	query := `
+		SELECT gu.user_id, COALESCE(u.username, ''), SUM(gu.calls) AS total
+		FROM gmaps_usage gu
+		LEFT JOIN users u ON u.id = gu.user_id
+		WHERE gu.day BETWEEN $1 AND $2
+		GROUP BY gu.user_id, u.username
+		ORDER BY total DESC
+		LIMIT $3
+	`
+	rows, err := r.db.QueryContext(ctx, query, from.Format(dateFormat), to.Format(dateFormat), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []entity.GmapsUsageUser
+	for rows.Next() {
+		var u entity.GmapsUsageUser
+		if err := rows.Scan(&u.UserID, &u.Username, &u.Calls); err != nil {
+			return nil, err
+		}
+		res = append(res, u)
+	}
+	return res, rows.Err()
+}
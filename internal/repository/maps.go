@@ -6,9 +6,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"proyek1/constant"
 	"proyek1/internal/entity"
 	"proyek1/utils"
+	"proyek1/utils/geo"
+	"strings"
 
+	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 )
 
@@ -24,23 +28,17 @@ func NewMapsRepository(db *sql.DB, log *logrus.Logger) *MapsRepo {
 	}
 }
 
-func (r *MapsRepo) GetTotalTempat(ctx context.Context, name string) (int, error) {
+func (r *MapsRepo) GetTotalTempat(ctx context.Context, filter entity.FilterTempat) (int, error) {
 	var total int
-	var err error
+	where, args := filterTempatQuery(filter, nil)
 	query := `
-		SELECT COUNT(DISTINCT tempat_pariwisata.place_id)
-		FROM tempat_pariwisata
-		INNER JOIN foto_tempat ON foto_tempat.place_id = tempat_pariwisata.place_id
-		WHERE tempat_pariwisata.deleted_at IS NULL
-	`
-	if name != "" {
-		query += " AND tempat_pariwisata.name ILIKE '%' || $1 || '%'"
-		err = r.db.QueryRowContext(ctx, query, name).Scan(&total)
-	} else {
-		err = r.db.QueryRowContext(ctx, query).Scan(&total)
-	}
+		SELECT COUNT(DISTINCT tp.place_id)
+		FROM tempat_pariwisata tp
+		INNER JOIN foto_tempat ON foto_tempat.place_id = tp.place_id
+		WHERE tp.deleted_at IS NULL
+	` + where
 
-	if err != nil {
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
 		return 0, err
 	}
 	return total, nil
@@ -148,13 +146,14 @@ func (r *MapsRepo) GetDetailTempat(ctx context.Context, id string) (entity.GetDe
 	return tempat, nil
 }
 
-func (r *MapsRepo) GetTempatPagination(ctx context.Context, name string, limit, offset int) ([]entity.Tempat, error) {
+func (r *MapsRepo) GetTempatPagination(ctx context.Context, filter entity.FilterTempat, limit, offset int) ([]entity.Tempat, error) {
 	var res []entity.Tempat
-	var rows *sql.Rows
-	var err error
+	where, args := filterTempatQuery(filter, nil)
+	args = append(args, limit, offset)
 	query := `
 	SELECT 
-		tempat_pariwisata.id, tempat_pariwisata.place_id, tempat_pariwisata.name, tempat_pariwisata.address, tempat_pariwisata.icon, 
+		tp.id, tp.place_id, tp.name, tp.address, tp.icon, COALESCE(tp.business_status, ''),
+		COALESCE(tp.province_code, ''), COALESCE(tp.province, ''), COALESCE(tp.regency, ''), COALESCE(tp.district, ''),
 		COALESCE(json_agg(DISTINCT jsonb_build_object(
 			'photo_reference', foto_tempat.photo_reference,
 			'width_px', foto_tempat.width_px,
@@ -165,25 +164,14 @@ func (r *MapsRepo) GetTempatPagination(ctx context.Context, name string, limit,
 			'open_time', opening_hours.open_time,
 			'close_time', opening_hours.close_time
 		)) FILTER (WHERE opening_hours.id IS NOT NULL), '[]') AS time
-	FROM tempat_pariwisata
-	LEFT JOIN foto_tempat ON foto_tempat.place_id = tempat_pariwisata.place_id
-	LEFT JOIN opening_hours ON opening_hours.place_id = tempat_pariwisata.place_id
-	WHERE tempat_pariwisata.deleted_at IS NULL
-`
-	if name != "" { // fitur search by name
-		query += " AND tempat_pariwisata.name ILIKE '%' || $1 || '%' "
-		query += ` GROUP BY tempat_pariwisata.id, tempat_pariwisata.place_id, tempat_pariwisata.name, tempat_pariwisata.address, tempat_pariwisata.icon
-			   LIMIT $2 OFFSET $3`
-		rows, err = r.db.QueryContext(ctx, query, name, limit, offset)
-	} else { // fitur get all biasa
-		query += ` GROUP BY tempat_pariwisata.id, tempat_pariwisata.place_id, tempat_pariwisata.name, tempat_pariwisata.address, tempat_pariwisata.icon
-					   LIMIT $1 OFFSET $2`
-		rows, err = r.db.QueryContext(ctx, query, limit, offset)
-	}
-
-	if err != nil {
-		return []entity.Tempat{}, err
-	}
+	FROM tempat_pariwisata tp
+	LEFT JOIN foto_tempat ON foto_tempat.place_id = tp.place_id
+	LEFT JOIN opening_hours ON opening_hours.place_id = tp.place_id
+	WHERE tp.deleted_at IS NULL` + where + fmt.Sprintf(`
+	GROUP BY tp.id, tp.place_id, tp.name, tp.address, tp.icon
+	LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -193,7 +181,8 @@ func (r *MapsRepo) GetTempatPagination(ctx context.Context, name string, limit,
 		var photoJson, timeJson []byte
 		var tempat entity.Tempat
 
-		if err := rows.Scan(&tempat.ID, &tempat.PlaceId, &tempat.Name, &tempat.Address, &tempat.Icon, &photoJson, &timeJson); err != nil {
+		if err := rows.Scan(&tempat.ID, &tempat.PlaceId, &tempat.Name, &tempat.Address, &tempat.Icon, &tempat.BusinessStatus,
+			&tempat.ProvinceCode, &tempat.Province, &tempat.Regency, &tempat.District, &photoJson, &timeJson); err != nil {
 			return nil, fmt.Errorf("error scanning row: %w", err)
 		}
 		if err := json.Unmarshal(photoJson, &tempat.Photos); err != nil {
@@ -208,6 +197,157 @@ func (r *MapsRepo) GetTempatPagination(ctx context.Context, name string, limit,
 	return res, nil
 }
 
+func (r *MapsRepo) GetKoordinatTempat(ctx context.Context, placeID string) (float64, float64, error) {
+	var lat, lng float64
+	query := `SELECT latitude, longtitude FROM tempat_pariwisata WHERE place_id = $1 AND deleted_at IS NULL`
+	if err := r.db.QueryRowContext(ctx, query, placeID).Scan(&lat, &lng); err != nil {
+		return 0, 0, err
+	}
+	return lat, lng, nil
+}
+
+// place_id yang sudah tersimpan (dan tidak dihapus) dari daftar yang diberikan
+func (r *MapsRepo) GetPlaceIDTersimpan(ctx context.Context, placeIDs []string) (map[string]bool, error) {
+	res := make(map[string]bool)
+	if len(placeIDs) == 0 {
+		return res, nil
+	}
+
+	query := `SELECT place_id FROM tempat_pariwisata WHERE place_id = ANY($1) AND deleted_at IS NULL`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(placeIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var placeID string
+		if err := rows.Scan(&placeID); err != nil {
+			return nil, err
+		}
+		res[placeID] = true
+	}
+	return res, rows.Err()
+}
+
+// Tempat yang paling lama belum dicek ulang, yang belum pernah di-resync duluan.
+// Tempat NOT_FOUND tidak diambil lagi karena place_id-nya sudah tidak bisa dipakai
+func (r *MapsRepo) GetPlaceIDBelumSync(ctx context.Context, limit int) ([]string, error) {
+	query := `SELECT place_id FROM tempat_pariwisata
+		WHERE deleted_at IS NULL AND COALESCE(business_status, '') <> $1
+		ORDER BY synced_at NULLS FIRST, created_at
+		LIMIT $2`
+	rows, err := r.db.QueryContext(ctx, query, constant.BusinessNotFound, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []string
+	for rows.Next() {
+		var placeID string
+		if err := rows.Scan(&placeID); err != nil {
+			return nil, err
+		}
+		res = append(res, placeID)
+	}
+	return res, rows.Err()
+}
+
+// Update data utama tempat dari hasil resync. Review, foto, jam buka dan kategori tidak disentuh
+func (r *MapsRepo) UpdateTempatSync(ctx context.Context, data *entity.Tempat) error {
+	query := `UPDATE tempat_pariwisata SET
+			name = $2, latitude = $3, longtitude = $4, geohash = $5, address = $6, icon = $7, business_status = $8,
+			province_code = COALESCE(NULLIF($9, ''), province_code), province = COALESCE(NULLIF($10, ''), province),
+			regency = COALESCE(NULLIF($11, ''), regency), district = COALESCE(NULLIF($12, ''), district),
+			synced_at = NOW(), updated_at = NOW()
+		WHERE place_id = $1 AND deleted_at IS NULL`
+	res, err := r.db.ExecContext(ctx, query, data.PlaceId, data.Name, data.Latitude, data.Longtitude, data.Geohash, data.Address, data.Icon, data.BusinessStatus,
+		data.ProvinceCode, data.Province, data.Regency, data.District)
+	if err != nil {
+		return utils.ParsePQError(err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return utils.ErrIDNotFound
+	}
+	return nil
+}
+
+func (r *MapsRepo) UpdateStatusTempat(ctx context.Context, placeID, status string) error {
+	query := `UPDATE tempat_pariwisata SET business_status = $2, synced_at = NOW(), updated_at = NOW()
+		WHERE place_id = $1 AND deleted_at IS NULL`
+	res, err := r.db.ExecContext(ctx, query, placeID, status)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return utils.ErrIDNotFound
+	}
+	return nil
+}
+
+// Pindahkan tempat dari place_id lama ke place_id baru dari Google, termasuk semua tabel anak.
+// Kalau place_id baru sudah tersimpan sebagai tempat lain, data anak digabung ke tempat itu
+// (jam buka pakai punya tempat baru) lalu tempat lama dihapus (soft delete)
+func (r *MapsRepo) PindahPlaceID(ctx context.Context, oldID, newID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// cek FK ditunda sampai commit, jadi urutan update parent dan anak bebas
+	if _, err := tx.ExecContext(ctx, `SET CONSTRAINTS fk_review_place, fk_photo_place, fk_opening_place,
+		category_pariwisata_place_id_fkey DEFERRED`); err != nil {
+		return err
+	}
+
+	var sudahAda bool
+	if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM tempat_pariwisata WHERE place_id = $1)`, newID).Scan(&sudahAda); err != nil {
+		return err
+	}
+
+	if sudahAda {
+		queries := []struct {
+			query string
+			args  []interface{}
+		}{
+			{`DELETE FROM opening_hours WHERE place_id = $1`, []interface{}{oldID}},
+			{`INSERT INTO category_pariwisata (place_id, category_code)
+				SELECT $2, category_code FROM category_pariwisata WHERE place_id = $1
+				ON CONFLICT (place_id, category_code) DO NOTHING`, []interface{}{oldID, newID}},
+			{`DELETE FROM category_pariwisata WHERE place_id = $1`, []interface{}{oldID}},
+			{`UPDATE tempat_pariwisata SET deleted_at = NOW(), updated_at = NOW() WHERE place_id = $1`, []interface{}{oldID}},
+			{`UPDATE tempat_pariwisata SET deleted_at = NULL, updated_at = NOW() WHERE place_id = $1`, []interface{}{newID}},
+		}
+		for _, q := range queries {
+			if _, err := tx.ExecContext(ctx, q.query, q.args...); err != nil {
+				return utils.ParsePQError(err)
+			}
+		}
+	} else {
+		if _, err := tx.ExecContext(ctx, `UPDATE tempat_pariwisata SET place_id = $2, updated_at = NOW() WHERE place_id = $1`, oldID, newID); err != nil {
+			return utils.ParsePQError(err)
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE opening_hours SET place_id = $2, updated_at = NOW() WHERE place_id = $1`, oldID, newID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE category_pariwisata SET place_id = $2, updated_at = NOW() WHERE place_id = $1`, oldID, newID); err != nil {
+			return err
+		}
+	}
+
+	// review dan foto selalu ikut pindah
+	if _, err := tx.ExecContext(ctx, `UPDATE review_tempat SET place_id = $2, updated_at = NOW() WHERE place_id = $1`, oldID, newID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE foto_tempat SET place_id = $2, updated_at = NOW() WHERE place_id = $1`, oldID, newID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func (r *MapsRepo) InsertTempat(ctx context.Context, data *entity.Tempat) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -216,9 +356,11 @@ func (r *MapsRepo) InsertTempat(ctx context.Context, data *entity.Tempat) error
 	defer tx.Rollback()
 
 	// Insert tempat
-	query := `INSERT INTO tempat_pariwisata (id, place_id, name, latitude, longtitude, address, icon, business_status)
-				  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
-	_, err = tx.ExecContext(ctx, query, data.ID, data.PlaceId, data.Name, data.Latitude, data.Longtitude, data.Address, data.Icon, data.BusinessStatus)
+	query := `INSERT INTO tempat_pariwisata (id, place_id, name, latitude, longtitude, geohash, address, icon, business_status, source,
+					province_code, province, regency, district)
+				  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''), NULLIF($12, ''), NULLIF($13, ''), NULLIF($14, ''))`
+	_, err = tx.ExecContext(ctx, query, data.ID, data.PlaceId, data.Name, data.Latitude, data.Longtitude, data.Geohash, data.Address, data.Icon, data.BusinessStatus, data.Source,
+		data.ProvinceCode, data.Province, data.Regency, data.District)
 	if err != nil {
 		return utils.ParsePQError(err)
 	}
@@ -311,3 +453,210 @@ func (r *MapsRepo) InsertType(ctx context.Context, tx *sql.Tx, data []entity.Typ
 	}
 	return nil
 }
+
+// Bangun kondisi WHERE dari filter, args lanjut dari index yang sudah ada
+func filterTempatQuery(f entity.FilterTempat, args []interface{}) (string, []interface{}) {
+	query := ""
+	if !f.IncludeClosed {
+		query += fmt.Sprintf(" AND COALESCE(tp.business_status, '') NOT IN ('%s', '%s')",
+			constant.BusinessClosedPermanently, constant.BusinessNotFound)
+	}
+	if f.Name != "" {
+		args = append(args, f.Name)
+		query += fmt.Sprintf(" AND tp.name ILIKE '%%' || $%d || '%%'", len(args))
+	}
+	if f.ProvinceCode != "" {
+		args = append(args, f.ProvinceCode)
+		query += fmt.Sprintf(" AND tp.province_code = $%d", len(args))
+	}
+	if f.Regency != "" {
+		args = append(args, f.Regency)
+		query += fmt.Sprintf(" AND lower(tp.regency) = lower($%d)", len(args))
+	}
+	if f.District != "" {
+		args = append(args, f.District)
+		query += fmt.Sprintf(" AND lower(tp.district) = lower($%d)", len(args))
+	}
+	if f.BBox != nil {
+		// Persempit kandidat pakai prefix geohash (index), baru cek koordinat persis
+		prefixes := geo.CoverBBox(geo.BBox{
+			MinLat: f.BBox.MinLat,
+			MinLng: f.BBox.MinLng,
+			MaxLat: f.BBox.MaxLat,
+			MaxLng: f.BBox.MaxLng,
+		}, constant.GeohashMaxCells)
+		if len(prefixes) > 0 && len(prefixes[0]) > 1 {
+			var likes []string
+			for _, p := range prefixes {
+				args = append(args, p+"%")
+				likes = append(likes, fmt.Sprintf("tp.geohash LIKE $%d", len(args)))
+			}
+			query += " AND (" + strings.Join(likes, " OR ") + ")"
+		}
+
+		args = append(args, f.BBox.MinLat, f.BBox.MaxLat, f.BBox.MinLng, f.BBox.MaxLng)
+		n := len(args)
+		query += fmt.Sprintf(" AND tp.latitude BETWEEN $%d AND $%d AND tp.longtitude BETWEEN $%d AND $%d", n-3, n-2, n-1, n)
+	}
+	return query, args
+}
+
+// Streaming per baris, tidak ditampung semua di memory
+func (r *MapsRepo) StreamTempatGeo(ctx context.Context, filter entity.FilterTempat, fn func(entity.TempatGeo) error) error {
+	query := `
+	SELECT
+		tp.place_id, tp.name, COALESCE(tp.address, ''), tp.latitude, tp.longtitude, COALESCE(tp.business_status, ''),
+		(SELECT COALESCE(AVG(rv.rating), 0) FROM review_tempat rv
+			WHERE rv.place_id = tp.place_id AND rv.deleted_at IS NULL) AS rating,
+		(SELECT COALESCE(json_agg(cp.category_code), '[]') FROM category_pariwisata cp
+			WHERE cp.place_id = tp.place_id AND cp.deleted_at IS NULL) AS categories
+	FROM tempat_pariwisata tp
+	WHERE tp.deleted_at IS NULL
+	`
+	where, args := filterTempatQuery(filter, nil)
+	query += where + " ORDER BY tp.id"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tempat entity.TempatGeo
+		var categoryJson []byte
+		if err := rows.Scan(&tempat.PlaceId, &tempat.Name, &tempat.Address, &tempat.Latitude, &tempat.Longtitude, &tempat.BusinessStatus, &tempat.Rating, &categoryJson); err != nil {
+			return fmt.Errorf("error scanning row: %w", err)
+		}
+		if err := json.Unmarshal(categoryJson, &tempat.Categories); err != nil {
+			return fmt.Errorf("error unmarshalling categories: %w", err)
+		}
+		if err := fn(tempat); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *MapsRepo) CountTempat(ctx context.Context, filter entity.FilterTempat) (int, error) {
+	var total int
+	where, args := filterTempatQuery(filter, nil)
+	query := `SELECT COUNT(*) FROM tempat_pariwisata tp WHERE tp.deleted_at IS NULL` + where
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// Kolom yang boleh dipakai untuk pengelompokan, jangan pernah dari input user langsung
+var regionColumns = map[string]string{
+	constant.RegionProvince: "tp.province_code",
+	constant.RegionRegency:  "tp.regency",
+	constant.RegionDistrict: "tp.district",
+}
+
+func (r *MapsRepo) CountTempatPerRegion(ctx context.Context, level string, filter entity.FilterTempat) ([]entity.RegionCount, error) {
+	column, ok := regionColumns[level]
+	if !ok {
+		return nil, fmt.Errorf("level wilayah tidak dikenal: %s", level)
+	}
+	where, args := filterTempatQuery(filter, nil)
+	query := fmt.Sprintf(`
+	SELECT %[1]s, COUNT(*)
+	FROM tempat_pariwisata tp
+	WHERE tp.deleted_at IS NULL AND %[1]s IS NOT NULL%[2]s
+	GROUP BY %[1]s
+	ORDER BY %[1]s`, column, where)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []entity.RegionCount
+	for rows.Next() {
+		var rc entity.RegionCount
+		if err := rows.Scan(&rc.Name, &rc.Count); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+		res = append(res, rc)
+	}
+	return res, rows.Err()
+}
+
+func (r *MapsRepo) GetTempatPoints(ctx context.Context, filter entity.FilterTempat, limit int) ([]entity.TempatPoint, error) {
+	where, args := filterTempatQuery(filter, nil)
+	args = append(args, limit)
+	query := `
+	SELECT tp.id, tp.place_id, tp.name, tp.latitude, tp.longtitude,
+		(SELECT COALESCE(AVG(rv.rating), 0) FROM review_tempat rv
+			WHERE rv.place_id = tp.place_id AND rv.deleted_at IS NULL) AS rating,
+		COALESCE(tp.business_status, '')
+	FROM tempat_pariwisata tp
+	WHERE tp.deleted_at IS NULL` + where + fmt.Sprintf(` ORDER BY rating DESC, tp.name LIMIT $%d`, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []entity.TempatPoint
+	for rows.Next() {
+		var p entity.TempatPoint
+		if err := rows.Scan(&p.ID, &p.PlaceId, &p.Name, &p.Latitude, &p.Longtitude, &p.Rating, &p.BusinessStatus); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+		res = append(res, p)
+	}
+	return res, rows.Err()
+}
+
+// Cluster grid dihitung di database, cellSize dalam derajat
+func (r *MapsRepo) GetTempatClusters(ctx context.Context, filter entity.FilterTempat, cellSize float64, limit int) ([]entity.TempatCluster, error) {
+	where, args := filterTempatQuery(filter, nil)
+	args = append(args, cellSize, limit)
+	cellIdx, limitIdx := len(args)-1, len(args)
+	query := fmt.Sprintf(`
+	WITH filtered AS (
+		SELECT tp.id, tp.place_id, tp.name, tp.latitude, tp.longtitude,
+			(SELECT COALESCE(AVG(rv.rating), 0) FROM review_tempat rv
+				WHERE rv.place_id = tp.place_id AND rv.deleted_at IS NULL) AS rating,
+			COALESCE(tp.business_status, '') AS business_status,
+			FLOOR(tp.latitude / $%[1]d) AS gy,
+			FLOOR(tp.longtitude / $%[1]d) AS gx
+		FROM tempat_pariwisata tp
+		WHERE tp.deleted_at IS NULL %[2]s
+	), ranked AS (
+		SELECT *,
+			ROW_NUMBER() OVER (PARTITION BY gy, gx ORDER BY rating DESC, name) AS rn,
+			COUNT(*) OVER (PARTITION BY gy, gx) AS cnt,
+			AVG(latitude) OVER (PARTITION BY gy, gx) AS clat,
+			AVG(longtitude) OVER (PARTITION BY gy, gx) AS clng
+		FROM filtered
+	)
+	SELECT cnt, clat, clng, id, place_id, name, latitude, longtitude, rating, business_status
+	FROM ranked
+	WHERE rn = 1
+	ORDER BY cnt DESC
+	LIMIT $%[3]d`, cellIdx, where, limitIdx)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []entity.TempatCluster
+	for rows.Next() {
+		var cl entity.TempatCluster
+		if err := rows.Scan(&cl.Count, &cl.Latitude, &cl.Longtitude,
+			&cl.Top.ID, &cl.Top.PlaceId, &cl.Top.Name, &cl.Top.Latitude, &cl.Top.Longtitude, &cl.Top.Rating, &cl.Top.BusinessStatus); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+		res = append(res, cl)
+	}
+	return res, rows.Err()
+}
@@ -67,7 +67,7 @@ func (r *RegistrasiRepo) Login(ctx context.Context, postData *entity.User) (*ent
 
 func (r *RegistrasiRepo) GetUserID(ctx context.Context, id string) (entity.User, error) {
 	var data entity.User
-	query := `SELECT id, email, username, password, role, photo_profile, is_active FROM "users" WHERE id = $1`
+	query := `SELECT id, email, username, password, role, photo_profile, is_active FROM "users" WHERE id = $1 AND deleted_at IS NULL`
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&data.ID,
 		&data.Email,
@@ -145,16 +145,6 @@ func (r *RegistrasiRepo) SoftDeleteOtpByID(ctx context.Context, id string) error
 	return err
 }
 
-func (r *RegistrasiRepo) ResetPassword(ctx context.Context, data *entity.User) error {
-	query := `UPDATE users SET password = $1 WHERE email = $2 AND deleted_at IS NULL`
-	_, err := r.db.ExecContext(ctx, query, data.Password, data.Email)
-	if err != nil {
-		return utils.ParsePQError(err)
-	}
-
-	return nil
-}
-
 func (r *RegistrasiRepo) EditDataUser(ctx context.Context, data *entity.User, id string) error {
 	query := `UPDATE users SET username = $1, password = $2, photo_profile = $3 WHERE id = $4 AND deleted_at IS NULL`
 	result, err := r.db.ExecContext(ctx, query, data.Username, data.Password, data.PhotoProfile, id)
@@ -170,21 +160,6 @@ func (r *RegistrasiRepo) EditDataUser(ctx context.Context, data *entity.User, id
 	return nil
 }
 
-func (r *RegistrasiRepo) ActivateAcount(ctx context.Context, email string) error {
-	query := `UPDATE users SET is_active = true WHERE email = $1 AND deleted_at IS NULL`
-	result, err := r.db.ExecContext(ctx, query, email)
-	if err != nil {
-		return utils.ParsePQError(err)
-	}
-
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("user tidak ditemukan atau sudah dihapus")
-	}
-
-	return nil
-}
-
 // Validasi
 func (r *RegistrasiRepo) IsDataAvailable(ctx context.Context, email, username string) bool {
 	var data string // cuman cek aja pakai string langsung, kalau mau ambil data ambil dari entity
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"proyek1/internal/entity"
+	"proyek1/utils"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type SessionRepo struct {
+	db  *sql.DB
+	log *logrus.Logger
+}
+
+func NewSessionRepository(db *sql.DB, log *logrus.Logger) *SessionRepo {
+	return &SessionRepo{
+		db:  db,
+		log: log,
+	}
+}
+
+// Sesi baru sekaligus refresh token pertamanya
+func (r *SessionRepo) CreateSession(ctx context.Context, s *entity.Session, tokenHash string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO user_sessions (id, users_id, device_name, user_agent, ip_address, expires_at)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), $6)`
+	if _, err := tx.ExecContext(ctx, query, s.ID, s.UserID, s.DeviceName, s.UserAgent, s.IP, s.ExpiresAt); err != nil {
+		return utils.ParsePQError(err)
+	}
+
+	qt := `INSERT INTO refresh_tokens (token_hash, session_id, expires_at) VALUES ($1, $2, $3)`
+	if _, err := tx.ExecContext(ctx, qt, tokenHash, s.ID, s.ExpiresAt); err != nil {
+		return utils.ParsePQError(err)
+	}
+
+	return tx.Commit()
+}
+
+func (r *SessionRepo) GetRefreshToken(ctx context.Context, tokenHash string) (entity.RefreshToken, error) {
+	var rt entity.RefreshToken
+	query := `SELECT rt.token_hash, rt.session_id, s.users_id, rt.expires_at, rt.used_at, s.expires_at, s.revoked_at
+		FROM refresh_tokens rt
+		INNER JOIN user_sessions s ON s.id = rt.session_id
+		WHERE rt.token_hash = $1`
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(&rt.TokenHash, &rt.SessionID, &rt.UserID,
+		&rt.ExpiresAt, &rt.UsedAt, &rt.SessionExpiresAt, &rt.SessionRevokedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.RefreshToken{}, utils.ErrRefreshToken
+	}
+	if err != nil {
+		return entity.RefreshToken{}, err
+	}
+	return rt, nil
+}
+
+// Tandai token lama terpakai lalu simpan token baru, masa berlaku sesi ikut diperpanjang.
+// Kalau token lama ternyata sudah terpakai (dua request refresh bersamaan) dikembalikan ErrRefreshReuse
+func (r *SessionRepo) RotateRefreshToken(ctx context.Context, oldHash, newHash, sessionID string, expiresAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE refresh_tokens SET used_at = NOW() WHERE token_hash = $1 AND used_at IS NULL`, oldHash)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return utils.ErrRefreshReuse
+	}
+
+	qt := `INSERT INTO refresh_tokens (token_hash, session_id, expires_at) VALUES ($1, $2, $3)`
+	if _, err := tx.ExecContext(ctx, qt, newHash, sessionID, expiresAt); err != nil {
+		return utils.ParsePQError(err)
+	}
+
+	qs := `UPDATE user_sessions SET expires_at = $2, last_used_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND revoked_at IS NULL`
+	if _, err := tx.ExecContext(ctx, qs, sessionID, expiresAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *SessionRepo) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	var active bool
+	query := `SELECT EXISTS (SELECT 1 FROM user_sessions WHERE id = $1 AND revoked_at IS NULL AND expires_at > NOW())`
+	if err := r.db.QueryRowContext(ctx, query, sessionID).Scan(&active); err != nil {
+		return false, err
+	}
+	return active, nil
+}
+
+// Cabut satu sesi milik user. Sesi yang sudah dicabut sebelumnya tidak diubah,
+// false kalau tidak ada sesi aktif dengan id itu
+func (r *SessionRepo) RevokeSession(ctx context.Context, userID, sessionID, reason string) (bool, error) {
+	query := `UPDATE user_sessions SET revoked_at = NOW(), revoked_reason = $3, updated_at = NOW()
+		WHERE id = $1 AND users_id = $2 AND revoked_at IS NULL`
+	res, err := r.db.ExecContext(ctx, query, sessionID, userID, reason)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// Sesi yang belum dicabut dan belum expired, terakhir dipakai paling atas
+func (r *SessionRepo) GetActiveSessions(ctx context.Context, userID string) ([]entity.Session, error) {
+	query := `SELECT id, users_id, COALESCE(device_name, ''), COALESCE(user_agent, ''), COALESCE(ip_address, ''),
+			expires_at, COALESCE(last_used_at, created_at), created_at
+		FROM user_sessions
+		WHERE users_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY last_used_at DESC NULLS LAST`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []entity.Session
+	for rows.Next() {
+		var s entity.Session
+		if err := rows.Scan(&s.ID, &s.UserID, &s.DeviceName, &s.UserAgent, &s.IP, &s.ExpiresAt, &s.LastUsedAt, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, s)
+	}
+	return res, rows.Err()
+}
+
+// Jumlah sesi user sepanjang waktu (termasuk yang sudah dicabut) dan berapa yang dari user agent yang sama,
+// dipakai untuk menentukan login dari perangkat baru
+func (r *SessionRepo) CountUserSessions(ctx context.Context, userID, userAgent string) (int, int, error) {
+	var total, sameDevice int
+	query := `SELECT COUNT(*), COUNT(*) FILTER (WHERE user_agent = NULLIF($2, ''))
+		FROM user_sessions WHERE users_id = $1`
+	if err := r.db.QueryRowContext(ctx, query, userID, userAgent).Scan(&total, &sameDevice); err != nil {
+		return 0, 0, err
+	}
+	return total, sameDevice, nil
+}
+
+func (r *SessionRepo) RevokeAllSessions(ctx context.Context, userID, reason string) (int64, error) {
+	query := `UPDATE user_sessions SET revoked_at = NOW(), revoked_reason = $2, updated_at = NOW()
+		WHERE users_id = $1 AND revoked_at IS NULL`
+	res, err := r.db.ExecContext(ctx, query, userID, reason)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
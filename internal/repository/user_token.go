@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"proyek1/constant"
+	"proyek1/internal/entity"
+	"proyek1/utils"
+)
+
+// Simpan token sekali pakai, token lain dengan keperluan sama yang belum terpakai ikut dimatikan
+// supaya hanya link/token terakhir yang berlaku
+func (r *RegistrasiRepo) CreateUserToken(ctx context.Context, t *entity.UserToken) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE user_tokens SET used_at = NOW() WHERE users_id = $1 AND purpose = $2 AND used_at IS NULL`
+	if _, err := tx.ExecContext(ctx, query, t.UserID, t.Purpose); err != nil {
+		return err
+	}
+
+	qi := `INSERT INTO user_tokens (token_hash, users_id, purpose, payload, expires_at) VALUES ($1, $2, $3, NULLIF($4, ''), $5)`
+	if _, err := tx.ExecContext(ctx, qi, t.TokenHash, t.UserID, t.Purpose, t.Payload, t.ExpiresAt); err != nil {
+		return utils.ParsePQError(err)
+	}
+
+	return tx.Commit()
+}
+
+// Pakai token sekaligus menjalankan perubahannya dalam satu transaksi: aktivasi akun, password baru
+// (data.Password, sudah di-hash, semua sesi ikut dicabut) atau email baru dari payload. Token hanya
+// berhasil sekali untuk keperluan yang cocok dan belum expired, kalau perubahan gagal token tidak ikut terpakai
+func (r *RegistrasiRepo) UseUserToken(ctx context.Context, tokenHash, purpose string, data *entity.User) (entity.UserToken, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entity.UserToken{}, err
+	}
+	defer tx.Rollback()
+
+	t := entity.UserToken{TokenHash: tokenHash, Purpose: purpose}
+	query := `UPDATE user_tokens SET used_at = NOW()
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING users_id, COALESCE(payload, ''), expires_at`
+	err = tx.QueryRowContext(ctx, query, tokenHash, purpose).Scan(&t.UserID, &t.Payload, &t.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.UserToken{}, utils.ErrPurposeToken
+	}
+	if err != nil {
+		return entity.UserToken{}, err
+	}
+
+	var qu string
+	var args []interface{}
+	switch purpose {
+	case constant.PurposeActivate:
+		qu, args = `UPDATE users SET is_active = true WHERE id = $1 AND deleted_at IS NULL`, []interface{}{t.UserID}
+	case constant.PurposeReset:
+		if data == nil || data.Password == "" {
+			return entity.UserToken{}, errors.New("password baru kosong")
+		}
+		qu, args = `UPDATE users SET password = $1 WHERE id = $2 AND deleted_at IS NULL`, []interface{}{data.Password, t.UserID}
+	case constant.PurposeChangeEmail:
+		if t.Payload == "" {
+			return entity.UserToken{}, utils.ErrPurposeToken
+		}
+		qu, args = `UPDATE users SET email = $1 WHERE id = $2 AND deleted_at IS NULL`, []interface{}{t.Payload, t.UserID}
+	default:
+		return entity.UserToken{}, fmt.Errorf("keperluan token tidak dikenal: %s", purpose)
+	}
+
+	result, err := tx.ExecContext(ctx, qu, args...)
+	if err != nil {
+		return entity.UserToken{}, utils.ParsePQError(err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return entity.UserToken{}, errors.New("user tidak ditemukan atau sudah dihapus")
+	}
+
+	if purpose == constant.PurposeReset {
+		// sesi lama ikut dicabut di transaksi yang sama, password tidak berganti kalau pencabutan gagal
+		revoke := `UPDATE user_sessions SET revoked_at = NOW(), revoked_reason = $2, updated_at = NOW()
+			WHERE users_id = $1 AND revoked_at IS NULL`
+		if _, err := tx.ExecContext(ctx, revoke, t.UserID, constant.RevokePassword); err != nil {
+			return entity.UserToken{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entity.UserToken{}, err
+	}
+	return t, nil
+}
+
+func (r *RegistrasiRepo) GetUserByEmail(ctx context.Context, email string) (entity.User, error) {
+	var data entity.User
+	query := `SELECT id, email, username, role, is_active FROM "users" WHERE email = $1 AND deleted_at IS NULL`
+	err := r.db.QueryRowContext(ctx, query, email).Scan(&data.ID, &data.Email, &data.Username, &data.Role, &data.IsActive)
+	if err != nil {
+		return entity.User{}, utils.ParsePQError(err)
+	}
+	return data, nil
+}
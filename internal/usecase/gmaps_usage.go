@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"proyek1/config"
+	"proyek1/constant"
+	"proyek1/internal/entity"
+	"proyek1/internal/model"
+	"proyek1/utils"
+	"proyek1/utils/gmaps"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const usageDayFormat = "2006-01-02"
+
+type RepositoryGmapsUsageInterface interface {
+	GetUsageTotals(ctx context.Context, from, to time.Time) (map[string]int, error)
+	GetUsageDaily(ctx context.Context, from, to time.Time) ([]entity.GmapsUsage, error)
+	GetUsageUsers(ctx context.Context, from, to time.Time, limit int) ([]entity.GmapsUsageUser, error)
+}
+
+type UsecaseGmapsUsage struct {
+	repo  RepositoryGmapsUsageInterface
+	quota config.GMAPS_QUOTA
+	log   *logrus.Logger
+}
+
+func NewGmapsUsageUsecase(repo RepositoryGmapsUsageInterface, quota config.GMAPS_QUOTA, log *logrus.Logger) *UsecaseGmapsUsage {
+	return &UsecaseGmapsUsage{
+		repo:  repo,
+		quota: quota,
+		log:   log,
+	}
+}
+
+// from/to format YYYY-MM-DD, default dari awal bulan sampai hari ini.
+// Data di tabel bisa telat sampai 30 detik karena meter menulis per batch
+func (s *UsecaseGmapsUsage) GetGmapsUsage(ctx context.Context, from, to string) (model.GmapsUsageReport, error) {
+	now := time.Now()
+	today, _ := time.Parse(usageDayFormat, now.Format(usageDayFormat))
+	monthStart := today.AddDate(0, 0, 1-today.Day())
+
+	fromDate, toDate := monthStart, today
+	var err error
+	if from != "" {
+		if fromDate, err = time.Parse(usageDayFormat, from); err != nil {
+			return model.GmapsUsageReport{}, utils.ErrUsageRange
+		}
+	}
+	if to != "" {
+		if toDate, err = time.Parse(usageDayFormat, to); err != nil {
+			return model.GmapsUsageReport{}, utils.ErrUsageRange
+		}
+	}
+	if toDate.Before(fromDate) || toDate.Sub(fromDate) > 366*24*time.Hour {
+		return model.GmapsUsageReport{}, utils.ErrUsageRange
+	}
+
+	totals, err := s.repo.GetUsageTotals(ctx, fromDate, toDate)
+	if err != nil {
+		return model.GmapsUsageReport{}, err
+	}
+	todayTotals, err := s.repo.GetUsageTotals(ctx, today, today)
+	if err != nil {
+		return model.GmapsUsageReport{}, err
+	}
+	monthTotals, err := s.repo.GetUsageTotals(ctx, monthStart, today)
+	if err != nil {
+		return model.GmapsUsageReport{}, err
+	}
+	daily, err := s.repo.GetUsageDaily(ctx, fromDate, toDate)
+	if err != nil {
+		return model.GmapsUsageReport{}, err
+	}
+	users, err := s.repo.GetUsageUsers(ctx, fromDate, toDate, constant.GmapsUsageTopUser)
+	if err != nil {
+		return model.GmapsUsageReport{}, err
+	}
+
+	res := model.GmapsUsageReport{
+		From:     fromDate.Format(usageDayFormat),
+		To:       toDate.Format(usageDayFormat),
+		Currency: "USD",
+	}
+	for _, ep := range gmaps.Endpoints {
+		price := s.price(ep)
+		item := model.GmapsUsageEndpoint{
+			Endpoint:      ep,
+			Calls:         totals[ep],
+			PricePer1000:  price,
+			EstimatedCost: estimateCost(totals[ep], price),
+			Today:         todayTotals[ep],
+			ThisMonth:     monthTotals[ep],
+			BudgetDaily:   s.quota.GMAPS_BUDGET_DAILY[ep],
+			BudgetMonthly: s.quota.GMAPS_BUDGET_MONTHLY[ep],
+		}
+		res.TotalCalls += item.Calls
+		res.EstimatedCost += item.EstimatedCost
+		res.Endpoints = append(res.Endpoints, item)
+	}
+	res.EstimatedCost = math.Round(res.EstimatedCost*100) / 100
+
+	// daily dari repo sudah urut per hari
+	for _, d := range daily {
+		day := d.Day.Format(usageDayFormat)
+		cost := estimateCost(d.Calls, s.price(d.Endpoint))
+		if n := len(res.Daily); n > 0 && res.Daily[n-1].Day == day {
+			res.Daily[n-1].Calls += d.Calls
+			res.Daily[n-1].EstimatedCost = math.Round((res.Daily[n-1].EstimatedCost+cost)*100) / 100
+			continue
+		}
+		res.Daily = append(res.Daily, model.GmapsUsageDaily{Day: day, Calls: d.Calls, EstimatedCost: cost})
+	}
+
+	for _, u := range users {
+		res.TopUsers = append(res.TopUsers, model.GmapsUsageUser{
+			UserID:   u.UserID,
+			Username: u.Username,
+			Calls:    u.Calls,
+		})
+	}
+	return res, nil
+}
+
+// Harga dari GMAPS_PRICES, kalau tidak ada pakai constant.GmapsPrices
+func (s *UsecaseGmapsUsage) price(endpoint string) float64 {
+	if p, ok := s.quota.GMAPS_PRICES[endpoint]; ok {
+		return p
+	}
+	return constant.GmapsPrices[endpoint]
+}
+
+func estimateCost(calls int, pricePer1000 float64) float64 {
+	return math.Round(float64(calls)*pricePer1000/1000*100) / 100
+}
@@ -2,13 +2,21 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"math"
+	"proyek1/constant"
 	"proyek1/internal/entity"
 	"proyek1/internal/model"
 	"proyek1/utils"
-	"proyek1/utils/gmaps"
+	"proyek1/utils/geo"
+	"proyek1/utils/places"
+	"proyek1/utils/region"
+	"proyek1/utils/routing"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -16,22 +24,37 @@ import (
 
 type RepositoryMapsInterface interface {
 	InsertTempat(ctx context.Context, data *entity.Tempat) error
-	GetTotalTempat(ctx context.Context, name string) (int, error)
-	GetTempatPagination(ctx context.Context, name string, limit, offset int) ([]entity.Tempat, error)
+	GetTotalTempat(ctx context.Context, filter entity.FilterTempat) (int, error)
+	GetTempatPagination(ctx context.Context, filter entity.FilterTempat, limit, offset int) ([]entity.Tempat, error)
+	CountTempatPerRegion(ctx context.Context, level string, filter entity.FilterTempat) ([]entity.RegionCount, error)
 	GetDetailTempat(ctx context.Context, id string) (entity.GetDetailTempat, error)
+	GetKoordinatTempat(ctx context.Context, placeID string) (float64, float64, error)
+	GetPlaceIDTersimpan(ctx context.Context, placeIDs []string) (map[string]bool, error)
+	StreamTempatGeo(ctx context.Context, filter entity.FilterTempat, fn func(entity.TempatGeo) error) error
+	CountTempat(ctx context.Context, filter entity.FilterTempat) (int, error)
+	GetTempatPoints(ctx context.Context, filter entity.FilterTempat, limit int) ([]entity.TempatPoint, error)
+	GetTempatClusters(ctx context.Context, filter entity.FilterTempat, cellSize float64, limit int) ([]entity.TempatCluster, error)
+	GetPlaceIDBelumSync(ctx context.Context, limit int) ([]string, error)
+	UpdateTempatSync(ctx context.Context, data *entity.Tempat) error
+	UpdateStatusTempat(ctx context.Context, placeID, status string) error
+	PindahPlaceID(ctx context.Context, oldID, newID string) error
 }
 
 type UsecaseMaps struct {
-	repo RepositoryMapsInterface
-	gm   gmaps.GmapsInterface
-	log  *logrus.Logger
+	repo   RepositoryMapsInterface
+	places places.Provider
+	route  routing.RoutingProvider
+	photo  utils.PhotoSignerInterface
+	log    *logrus.Logger
 }
 
-func NewMapsUsercase(repo RepositoryMapsInterface, log *logrus.Logger, gm gmaps.GmapsInterface) *UsecaseMaps {
+func NewMapsUsercase(repo RepositoryMapsInterface, log *logrus.Logger, places places.Provider, route routing.RoutingProvider, photo utils.PhotoSignerInterface) *UsecaseMaps {
 	return &UsecaseMaps{
-		repo: repo,
-		log:  log,
-		gm:   gm,
+		repo:   repo,
+		log:    log,
+		places: places,
+		route:  route,
+		photo:  photo,
 	}
 }
 
@@ -39,7 +62,7 @@ func (s *UsecaseMaps) InsertTempat(ctx context.Context, placeId string) error {
 	if placeId == "" {
 		return errors.New("Id tidak ditemukan atau kosong")
 	}
-	dataGmaps, err := s.gm.GmapsSearchByPlaceID(placeId)
+	dataGmaps, err := s.places.Details(ctx, placeId, "")
 	if err != nil {
 		return err
 	}
@@ -51,14 +74,39 @@ func (s *UsecaseMaps) InsertTempat(ctx context.Context, placeId string) error {
 
 	return nil
 }
-func (s *UsecaseMaps) GetTempatPagination(ctx context.Context, name string, limit, page int) ([]model.GetAllTempat, int, error) {
-	total, err := s.repo.GetTotalTempat(ctx, name)
+
+// Prediksi dari Google ditandai Local kalau tempatnya sudah ada di database.
+// Gagal cek database tidak menggagalkan autocomplete, semua dianggap belum tersimpan
+func (s *UsecaseMaps) Autocomplete(ctx context.Context, input string, opts model.SearchOptions) ([]model.Prediction, error) {
+	predictions, err := s.places.Autocomplete(ctx, input, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	placeIDs := make([]string, 0, len(predictions))
+	for _, p := range predictions {
+		placeIDs = append(placeIDs, p.PlaceID)
+	}
+	tersimpan, err := s.repo.GetPlaceIDTersimpan(ctx, placeIDs)
+	if err != nil {
+		s.log.Warnf("Gagal mengecek tempat tersimpan untuk autocomplete: %v", err)
+		return predictions, nil
+	}
+	for i := range predictions {
+		predictions[i].Local = tersimpan[predictions[i].PlaceID]
+	}
+	return predictions, nil
+}
+
+func (s *UsecaseMaps) GetTempatPagination(ctx context.Context, filter model.FilterTempat, limit, page int) ([]model.GetAllTempat, int, error) {
+	entityFilter := toEntityFilter(filter)
+	total, err := s.repo.GetTotalTempat(ctx, entityFilter)
 	if err != nil {
 		return []model.GetAllTempat{}, 0, err
 	}
 	totalPage := utils.TotalPageForPagination(total, limit)
 	offset := (page - 1) * limit
-	dataTempat, err := s.repo.GetTempatPagination(ctx, name, limit, offset)
+	dataTempat, err := s.repo.GetTempatPagination(ctx, entityFilter, limit, offset)
 	if err != nil {
 		return []model.GetAllTempat{}, 0, err
 	}
@@ -66,10 +114,17 @@ func (s *UsecaseMaps) GetTempatPagination(ctx context.Context, name string, limi
 	var res []model.GetAllTempat
 	for _, v := range dataTempat {
 		tempat := model.GetAllTempat{
-			ID:      v.ID,
-			PlaceId: v.PlaceId,
-			Name:    v.Name,
-			Address: v.Address,
+			ID:           v.ID,
+			PlaceId:      v.PlaceId,
+			Name:         v.Name,
+			Address:      v.Address,
+			ProvinceCode: v.ProvinceCode,
+			Province:     v.Province,
+			Regency:      v.Regency,
+			District:     v.District,
+
+			BusinessStatus:    v.BusinessStatus,
+			TemporarilyClosed: v.BusinessStatus == constant.BusinessClosedTemporarily,
 		}
 
 		var hours []model.HourTempatGetAll
@@ -83,17 +138,11 @@ func (s *UsecaseMaps) GetTempatPagination(ctx context.Context, name string, limi
 
 		var foto []model.FotoTempatGetAll
 		for _, f := range v.Photos {
-			// _, err := s.gm.PhotoReference(f.PhotoRefrences)
-			// if err != nil {
-			// 	s.log.Warn("Photo reference error: ", err)
-			// 	continue
-			// }
-
-			proxyURL := f.PhotoRefrences
 			foto = append(foto, model.FotoTempatGetAll{
 				WidthPx:        f.WidthPx,
 				HeightPx:       f.HeightPx,
-				PhotoRefrences: proxyURL,
+				PhotoRefrences: f.PhotoRefrences,
+				PhotoURL:       s.photo.SignPhoto(f.PhotoRefrences, constant.PhotoDefaultWidth, 0),
 			})
 		}
 
@@ -107,6 +156,124 @@ func (s *UsecaseMaps) GetTempatPagination(ctx context.Context, name string, limi
 	return res, totalPage, nil
 }
 
+// Tanpa provinsi: semua provinsi (termasuk yang belum ada tempatnya), dengan provinsi: kabupaten/kota
+// di provinsi itu, dengan provinsi dan kabupaten/kota: kecamatan
+func (s *UsecaseMaps) GetRegions(ctx context.Context, filter model.FilterTempat) (model.Regions, error) {
+	level := constant.RegionProvince
+	switch {
+	case filter.ProvinceCode != "" && filter.Regency != "":
+		level = constant.RegionDistrict
+	case filter.ProvinceCode != "":
+		level = constant.RegionRegency
+	}
+
+	counts, err := s.repo.CountTempatPerRegion(ctx, level, toEntityFilter(filter))
+	if err != nil {
+		return model.Regions{}, err
+	}
+
+	res := model.Regions{Level: level, Regions: []model.RegionCount{}}
+	if level != constant.RegionProvince {
+		for _, c := range counts {
+			res.Regions = append(res.Regions, model.RegionCount{Name: c.Name, Count: c.Count})
+		}
+		return res, nil
+	}
+
+	perCode := make(map[string]int)
+	for _, c := range counts {
+		perCode[c.Name] = c.Count
+	}
+	for _, p := range region.Provinces {
+		res.Regions = append(res.Regions, model.RegionCount{Code: p.Code, Name: p.Name, Count: perCode[p.Code]})
+	}
+	return res, nil
+}
+
+func toEntityFilter(filter model.FilterTempat) entity.FilterTempat {
+	entityFilter := entity.FilterTempat{
+		Name:          filter.Name,
+		ProvinceCode:  filter.ProvinceCode,
+		Regency:       filter.Regency,
+		District:      filter.District,
+		IncludeClosed: filter.IncludeClosed,
+	}
+	if filter.BBox != nil {
+		entityFilter.BBox = &entity.BBox{
+			MinLat: filter.BBox.MinLat,
+			MinLng: filter.BBox.MinLng,
+			MaxLat: filter.BBox.MaxLat,
+			MaxLng: filter.BBox.MaxLng,
+		}
+	}
+	return entityFilter
+}
+
+// Cek ulang tempat ke Google/OSM. Dengan placeID hanya tempat itu, tanpa placeID sebanyak limit
+// tempat yang paling lama belum dicek. Gagal per tempat dicatat di hasil, kecuali quota habis
+func (s *UsecaseMaps) ResyncTempat(ctx context.Context, placeID string, limit int) ([]model.ResyncTempat, error) {
+	placeIDs := []string{placeID}
+	if placeID == "" {
+		ids, err := s.repo.GetPlaceIDBelumSync(ctx, limit)
+		if err != nil {
+			return nil, err
+		}
+		placeIDs = ids
+	}
+
+	res := []model.ResyncTempat{}
+	for _, id := range placeIDs {
+		hasil, err := s.resyncSatuTempat(ctx, id)
+		if err != nil {
+			if placeID != "" || errors.Is(err, utils.ErrQuotaExceeded) {
+				return res, err
+			}
+			s.log.Warnf("Gagal resync tempat %s: %v", id, err)
+			hasil = model.ResyncTempat{PlaceID: id, Error: err.Error()}
+		}
+		res = append(res, hasil)
+	}
+	return res, nil
+}
+
+func (s *UsecaseMaps) resyncSatuTempat(ctx context.Context, placeID string) (model.ResyncTempat, error) {
+	res := model.ResyncTempat{PlaceID: placeID}
+
+	data, err := s.places.Details(utils.WithFreshData(ctx), placeID, "")
+	switch {
+	case errors.Is(err, utils.ErrGmapsNotFound), errors.Is(err, utils.ErrGmapsZeroResults), errors.Is(err, utils.ErrIDNotFound):
+		// place_id sudah tidak dikenal dan tidak ada penggantinya
+		if err := s.repo.UpdateStatusTempat(ctx, placeID, constant.BusinessNotFound); err != nil {
+			return res, err
+		}
+		res.BusinessStatus = constant.BusinessNotFound
+		res.Hidden = true
+		return res, nil
+	case err != nil:
+		return res, err
+	}
+
+	// Google mengembalikan place_id baru kalau place_id lama sudah diganti
+	if data.PlaceID != "" && data.PlaceID != placeID {
+		if err := s.repo.PindahPlaceID(ctx, placeID, data.PlaceID); err != nil {
+			return res, err
+		}
+		s.log.Infof("place_id %s dipindah ke %s", placeID, data.PlaceID)
+		res.NewPlaceID = data.PlaceID
+	}
+
+	tempat := ConverMapsToModelPlace(data)
+	if tempat.BusinessStatus == "" {
+		tempat.BusinessStatus = constant.BusinessOperational
+	}
+	if err := s.repo.UpdateTempatSync(ctx, tempat); err != nil {
+		return res, err
+	}
+	res.BusinessStatus = tempat.BusinessStatus
+	res.Hidden = tempat.BusinessStatus == constant.BusinessClosedPermanently
+	return res, nil
+}
+
 func (s *UsecaseMaps) GetDetailTempat(ctx context.Context, id string) (model.GetDetailTempat, error) {
 	if id == "" {
 		return model.GetDetailTempat{}, errors.New("Id tidak ditemukan atau kosong")
@@ -143,11 +310,12 @@ func (s *UsecaseMaps) GetDetailTempat(ctx context.Context, id string) (model.Get
 		})
 	}
 	var photos []model.Photo
-	for _, s := range resData.Photos {
+	for _, p := range resData.Photos {
 		photos = append(photos, model.Photo{
-			WidthPx:        s.WidthPx,
-			HeightPx:       s.HeightPx,
-			PhotoReference: s.PhotoRefrences,
+			WidthPx:        p.WidthPx,
+			HeightPx:       p.HeightPx,
+			PhotoReference: p.PhotoRefrences,
+			PhotoURL:       s.photo.SignPhoto(p.PhotoRefrences, constant.PhotoDefaultWidth, 0),
 		})
 	}
 	var types []model.Type
@@ -173,22 +341,80 @@ func (s *UsecaseMaps) GetDetailTempat(ctx context.Context, id string) (model.Get
 			resData.Lat,
 			resData.Lng,
 			id),
-		Photos:         photos,
-		Types:          types,
-		BusinessStatus: resData.BusinessStatus,
+		Photos:            photos,
+		Types:             types,
+		BusinessStatus:    resData.BusinessStatus,
+		TemporarilyClosed: resData.BusinessStatus == constant.BusinessClosedTemporarily,
 	}
 
 	return results, nil
 }
 
-func (s *UsecaseMaps) RouteDestination(ctx context.Context, req model.RequestRouteMaps, placeID string) (*model.ResponseRouteMaps, error) {
-	searchData, err := s.gm.GmapsSearchByPlaceID(placeID)
+func (s *UsecaseMaps) RouteDestination(ctx context.Context, req model.RequestRouteOptions, placeID string) (*model.ResponseRouteMaps, error) {
+	reqData, err := buildRouteRequest(req, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	floatLat, floatLng, err := s.koordinatTujuan(ctx, placeID)
 	if err != nil {
 		return nil, err
 	}
+	reqData.Destination = model.Waypoint{
+		Location: model.LocationReq{
+			LatLng: model.LatLng{
+				Latitude:  floatLat,
+				Longitude: floatLng,
+			},
+		},
+	}
+
+	res, err := s.route.Route(ctx, reqData)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Routes) > req.Alternatives+1 {
+		res.Routes = res.Routes[:req.Alternatives+1]
+	}
+	return res, nil
+}
+
+// Koordinat tujuan diambil dari tempat_pariwisata dulu, Place Details hanya kalau tempat belum tersimpan
+func (s *UsecaseMaps) koordinatTujuan(ctx context.Context, placeID string) (float64, float64, error) {
+	lat, lng, err := s.repo.GetKoordinatTempat(ctx, placeID)
+	if err == nil {
+		return lat, lng, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		s.log.Warnf("Gagal mengambil koordinat tempat %s dari database: %v", placeID, err)
+	}
+
+	searchData, err := s.places.Details(ctx, placeID, "")
+	if err != nil {
+		return 0, 0, err
+	}
+	lat, _ = strconv.ParseFloat(searchData.Geometry.Lat, 64)
+	lng, _ = strconv.ParseFloat(searchData.Geometry.Lng, 64)
+	return lat, lng, nil
+}
+
+// Validasi opsi rute dari client lalu dipetakan ke body Routes API (tanpa destination)
+func buildRouteRequest(req model.RequestRouteOptions, now time.Time) (model.RequestRouteMaps, error) {
+	mode := strings.ToUpper(strings.TrimSpace(req.TravelMode))
+	if mode == "" {
+		mode = constant.TravelDrive
+	}
+	switch mode {
+	case constant.TravelDrive, constant.TravelTwoWheeler, constant.TravelWalk, constant.TravelBicycle, constant.TravelTransit:
+	default:
+		return model.RequestRouteMaps{}, utils.ErrTravelMode
+	}
+	motorized := mode == constant.TravelDrive || mode == constant.TravelTwoWheeler
+
+	if req.Alternatives < 0 || req.Alternatives > constant.MaxAlternatives {
+		return model.RequestRouteMaps{}, utils.ErrRouteAlternates
+	}
 
-	floatLat, _ := strconv.ParseFloat(searchData.Geometry.Lat, 64)
-	floatLng, _ := strconv.ParseFloat(searchData.Geometry.Lng, 64)
 	reqData := model.RequestRouteMaps{
 		Origin: model.Waypoint{
 			Location: model.LocationReq{
@@ -197,22 +423,143 @@ func (s *UsecaseMaps) RouteDestination(ctx context.Context, req model.RequestRou
 					Longitude: req.Origin.Location.LatLng.Longitude,
 				},
 			},
-		}, Destination: model.Waypoint{
-			Location: model.LocationReq{
-				LatLng: model.LatLng{
-					Latitude:  floatLat,
-					Longitude: floatLng,
-				},
+		},
+		TravelMode:               mode,
+		ComputeAlternativeRoutes: req.Alternatives > 0,
+		LanguageCode:             "id",
+	}
+
+	if req.AvoidTolls || req.AvoidHighways || req.AvoidFerries {
+		if !motorized {
+			return model.RequestRouteMaps{}, utils.ErrRouteModifier
+		}
+		reqData.RouteModifiers = &model.RouteModifiers{
+			AvoidTolls:    req.AvoidTolls,
+			AvoidHighways: req.AvoidHighways,
+			AvoidFerries:  req.AvoidFerries,
+		}
+	}
+
+	if req.TrafficAware {
+		if !motorized {
+			return model.RequestRouteMaps{}, utils.ErrTrafficAware
+		}
+		reqData.RoutingPreference = "TRAFFIC_AWARE"
+	}
+
+	if req.DepartureTime != "" {
+		departure, err := time.Parse(time.RFC3339, req.DepartureTime)
+		if err != nil {
+			return model.RequestRouteMaps{}, utils.ErrDepartureTime
+		}
+		// Selain TRANSIT, Routes API menolak waktu berangkat di masa lalu
+		if mode != constant.TravelTransit && departure.Before(now.Add(-time.Minute)) {
+			return model.RequestRouteMaps{}, utils.ErrDepartureTime
+		}
+		reqData.DepartureTime = departure.UTC().Format(time.RFC3339)
+		// departureTime diabaikan kalau routing tidak memperhitungkan lalu lintas
+		if motorized {
+			reqData.RoutingPreference = "TRAFFIC_AWARE"
+		}
+	}
+
+	return reqData, nil
+}
+
+func (s *UsecaseMaps) StreamTempatGeoJSON(ctx context.Context, filter model.FilterTempat, fn func(model.GeoJSONFeature) error) error {
+	entityFilter := toEntityFilter(filter)
+
+	return s.repo.StreamTempatGeo(ctx, entityFilter, func(t entity.TempatGeo) error {
+		categories := t.Categories
+		if categories == nil {
+			categories = []string{}
+		}
+		return fn(model.GeoJSONFeature{
+			Type: "Feature",
+			Geometry: model.GeoJSONGeometry{
+				Type:        "Point",
+				Coordinates: []float64{t.Longtitude, t.Latitude},
 			},
+			Properties: model.GeoJSONProperties{
+				PlaceID:           t.PlaceId,
+				Name:              t.Name,
+				Address:           t.Address,
+				Categories:        categories,
+				Rating:            t.Rating,
+				BusinessStatus:    t.BusinessStatus,
+				TemporarilyClosed: t.BusinessStatus == constant.BusinessClosedTemporarily,
+			},
+		})
+	})
+}
+
+func (s *UsecaseMaps) GetTempatViewport(ctx context.Context, bbox model.BBox, zoom int) (model.ViewportTempat, error) {
+	filter := entity.FilterTempat{
+		BBox: &entity.BBox{
+			MinLat: bbox.MinLat,
+			MinLng: bbox.MinLng,
+			MaxLat: bbox.MaxLat,
+			MaxLng: bbox.MaxLng,
 		},
-		TravelMode: req.TravelMode,
 	}
-	fmt.Println("Hasil pencarian placeID:", searchData.Geometry.Lat, searchData.Geometry.Lng)
 
-	return s.gm.RouteToDestination(reqData)
+	total, err := s.repo.CountTempat(ctx, filter)
+	if err != nil {
+		return model.ViewportTempat{}, err
+	}
+
+	res := model.ViewportTempat{Total: total}
+	if zoom >= constant.ViewportClusterZoom && total <= constant.ViewportMaxFeatures {
+		points, err := s.repo.GetTempatPoints(ctx, filter, constant.ViewportMaxFeatures)
+		if err != nil {
+			return model.ViewportTempat{}, err
+		}
+		res.Mode = "places"
+		res.Places = []model.ViewportPlace{}
+		for _, p := range points {
+			res.Places = append(res.Places, ConvertTempatPoint(p))
+		}
+		return res, nil
+	}
+
+	// 1 tile = 360/2^zoom derajat, dibagi 4 supaya cluster tidak terlalu besar di layar
+	cellSize := 360 / (math.Pow(2, float64(zoom)) * 4)
+	clusters, err := s.repo.GetTempatClusters(ctx, filter, cellSize, constant.ViewportMaxFeatures+1)
+	if err != nil {
+		return model.ViewportTempat{}, err
+	}
+	if len(clusters) > constant.ViewportMaxFeatures {
+		clusters = clusters[:constant.ViewportMaxFeatures]
+		res.Truncated = true
+	}
+
+	res.Mode = "clusters"
+	res.Clusters = []model.ViewportCluster{}
+	for _, cl := range clusters {
+		res.Clusters = append(res.Clusters, model.ViewportCluster{
+			Count:    cl.Count,
+			Lat:      cl.Latitude,
+			Lng:      cl.Longtitude,
+			TopPlace: ConvertTempatPoint(cl.Top),
+		})
+	}
+	return res, nil
 }
 
 // Convert
+func ConvertTempatPoint(p entity.TempatPoint) model.ViewportPlace {
+	return model.ViewportPlace{
+		ID:      p.ID,
+		PlaceID: p.PlaceId,
+		Name:    p.Name,
+		Lat:     p.Latitude,
+		Lng:     p.Longtitude,
+		Rating:  p.Rating,
+
+		TemporarilyClosed: p.BusinessStatus == constant.BusinessClosedTemporarily,
+	}
+}
+
 func ConverMapsToModelPlace(req model.MapsGetByPlaceId) *entity.Tempat {
 	lat, _ := strconv.ParseFloat(req.Geometry.Lat, 64)
 	lng, _ := strconv.ParseFloat(req.Geometry.Lng, 64)
@@ -222,10 +569,16 @@ func ConverMapsToModelPlace(req model.MapsGetByPlaceId) *entity.Tempat {
 		Name:           req.Name,
 		Latitude:       lat,
 		Longtitude:     lng,
+		Geohash:        geo.Encode(lat, lng, constant.GeohashPrecision),
 		Address:        req.FormattedAddress,
 		Icon:           req.Icon,
 		BusinessStatus: req.BusinessStatus,
+		Source:         req.Source,
+	}
+	if conv.Source == "" {
+		conv.Source = places.SourceOf(req.PlaceID)
 	}
+	setRegion(conv, req.Address)
 
 	var rev []entity.Review
 	for _, v := range req.Reviews {
@@ -281,3 +634,19 @@ func ConverMapsToModelPlace(req model.MapsGetByPlaceId) *entity.Tempat {
 
 	return conv
 }
+
+// Provinsi dicocokkan ke tabel utils/region, kabupaten/kota dan kecamatan dibakukan namanya.
+// Tempat di luar Indonesia tidak diberi wilayah
+func setRegion(t *entity.Tempat, addr model.Address) {
+	if addr.CountryCode != "" && !strings.EqualFold(addr.CountryCode, "ID") {
+		return
+	}
+	if p, ok := region.FindProvince(addr.Province); ok {
+		t.ProvinceCode = p.Code
+		t.Province = p.Name
+	} else {
+		t.Province = addr.Province
+	}
+	t.Regency = region.NormalizeRegency(addr.City)
+	t.District = region.NormalizeDistrict(addr.District)
+}
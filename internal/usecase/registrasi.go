@@ -8,6 +8,7 @@ import (
 	"html/template"
 	"math/rand"
 	"proyek1/config"
+	"proyek1/constant"
 	"proyek1/internal/entity"
 	"proyek1/internal/model"
 	"proyek1/utils"
@@ -25,8 +26,10 @@ import (
 var (
 	RoleUser   = "users"
 	RoleAdmin  = "admin"
-	RoleForgot = "forgot"
 	SubjectOTP = "OTP Reset Password"
+
+	SubjectNewDevice   = "Login dari Perangkat Baru"
+	SubjectChangeEmail = "Konfirmasi Email Baru"
 )
 
 type RepositoryUserInterface interface {
@@ -36,30 +39,34 @@ type RepositoryUserInterface interface {
 	ForgotPassword(ctx context.Context, data *entity.Otp) error // Nanti
 	OtpVerify(ctx context.Context, email string, otp int) (*entity.Otp, error)
 	SoftDeleteOtpByID(ctx context.Context, id string) error
-	ResetPassword(ctx context.Context, data *entity.User) error
 
 	EditDataUser(ctx context.Context, data *entity.User, id string) error
 	//
 	IsDataAvailable(ctx context.Context, email, username string) bool
 	RoleChecker(ctx context.Context, id string) string
-	ActivateAcount(ctx context.Context, email string) error
+
+	CreateUserToken(ctx context.Context, t *entity.UserToken) error
+	UseUserToken(ctx context.Context, tokenHash, purpose string, data *entity.User) (entity.UserToken, error)
+	GetUserByEmail(ctx context.Context, email string) (entity.User, error)
 }
 
 type UsecaseUser struct {
-	jwt      jwt.JWTInterface
-	userRepo RepositoryUserInterface
-	log      *logrus.Logger
-	cfg      *config.Config
-	m        mailer.MailInterface
+	jwt         jwt.JWTInterface
+	userRepo    RepositoryUserInterface
+	sessionRepo RepositorySessionInterface
+	log         *logrus.Logger
+	cfg         *config.Config
+	m           mailer.MailInterface
 }
 
-func NewUserUsecase(jwt jwt.JWTInterface, user RepositoryUserInterface, log *logrus.Logger, cfg *config.Config, m mailer.MailInterface) *UsecaseUser {
+func NewUserUsecase(jwt jwt.JWTInterface, user RepositoryUserInterface, session RepositorySessionInterface, log *logrus.Logger, cfg *config.Config, m mailer.MailInterface) *UsecaseUser {
 	return &UsecaseUser{
-		jwt:      jwt,
-		userRepo: user,
-		log:      log,
-		cfg:      cfg,
-		m:        m,
+		jwt:         jwt,
+		userRepo:    user,
+		sessionRepo: session,
+		log:         log,
+		cfg:         cfg,
+		m:           m,
 	}
 }
 
@@ -112,7 +119,7 @@ func (s *UsecaseUser) Register(ctx context.Context, userData *model.User) error
 		return err
 	}
 
-	token, err := s.jwt.GenerateToken(&model.User{ID: resData.ID, Email: resData.Email, Role: resData.Role})
+	token, err := s.issueToken(ctx, resData.ID, constant.PurposeActivate, "", constant.ActivateTokenTTL*time.Hour)
 	if err != nil {
 		return err
 	}
@@ -165,14 +172,11 @@ func (s *UsecaseUser) Login(ctx context.Context, postData *model.Login) (*model.
 		Email: res.Email,
 		Role:  userRole,
 	}
-	token, err := s.jwt.GenerateToken(&tokenData)
+	UserLoginData, err := s.newSession(ctx, tokenData, postData)
 	if err != nil {
 		return &model.Login{}, err
 	}
 
-	UserLoginData := &model.Login{}
-	UserLoginData.Token = token
-
 	return UserLoginData, nil
 }
 
@@ -389,13 +393,11 @@ func (s *UsecaseUser) OtpVerify(ctx context.Context, req *model.Otp) (*model.Otp
 		return nil, fmt.Errorf("gagal menghapus otp: %v", err)
 	}
 
-	var modelUser model.User
-	modelUser = model.User{
-		ID:    req.ID,
-		Email: req.Email,
-		Role:  RoleForgot,
+	user, err := s.userRepo.GetUserByEmail(ctx, data.Email)
+	if err != nil {
+		return nil, err
 	}
-	token, err := s.jwt.GenerateToken(&modelUser)
+	token, err := s.issueToken(ctx, user.ID, constant.PurposeReset, "", constant.ResetTokenTTL*time.Minute)
 	if err != nil {
 		return nil, err
 	}
@@ -404,7 +406,11 @@ func (s *UsecaseUser) OtpVerify(ctx context.Context, req *model.Otp) (*model.Otp
 	return tokenData, nil
 }
 
-func (s *UsecaseUser) ResetPassword(ctx context.Context, req *model.User) error {
+// Token dari OtpVerify hanya bisa dipakai sekali, setelah password diganti semua sesi login dicabut
+func (s *UsecaseUser) ResetPassword(ctx context.Context, token string, req *model.User) error {
+	if token == "" {
+		return utils.ErrPurposeToken
+	}
 	if req.Password != req.ConfirmPassword {
 		return errors.New("konfirmasi password tidak sama dengan password")
 	}
@@ -419,27 +425,17 @@ func (s *UsecaseUser) ResetPassword(ctx context.Context, req *model.User) error
 		return err
 	}
 
-	var entityModel entity.User
-	entityModel = entity.User{
-		ID:       req.ID,
-		Email:    req.Email,
-		Password: hashedPassword,
-	}
-
-	err = s.userRepo.ResetPassword(ctx, &entityModel)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	// token terpakai, password diganti dan sesi dicabut dalam satu transaksi
+	_, err = s.userRepo.UseUserToken(ctx, utils.HashToken(token), constant.PurposeReset, &entity.User{Password: hashedPassword})
+	return err
 }
 
-func (s *UsecaseUser) ActivateAcount(ctx context.Context, email string) error {
-	if email == "" {
-		return errors.New("email tidak boleh kosong")
+func (s *UsecaseUser) ActivateAcount(ctx context.Context, token string) error {
+	if token == "" {
+		return utils.ErrPurposeToken
 	}
 
-	err := s.userRepo.ActivateAcount(ctx, email)
+	_, err := s.userRepo.UseUserToken(ctx, utils.HashToken(token), constant.PurposeActivate, nil)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"html/template"
+	"proyek1/constant"
+	"proyek1/internal/entity"
+	"proyek1/internal/model"
+	"proyek1/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RepositorySessionInterface interface {
+	CreateSession(ctx context.Context, s *entity.Session, tokenHash string) error
+	GetRefreshToken(ctx context.Context, tokenHash string) (entity.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, oldHash, newHash, sessionID string, expiresAt time.Time) error
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
+	RevokeSession(ctx context.Context, userID, sessionID, reason string) (bool, error)
+	RevokeAllSessions(ctx context.Context, userID, reason string) (int64, error)
+	GetActiveSessions(ctx context.Context, userID string) ([]entity.Session, error)
+	CountUserSessions(ctx context.Context, userID, userAgent string) (int, int, error)
+}
+
+// Buat sesi baru untuk user yang berhasil login, hasilnya access token + refresh token.
+// Login dari perangkat yang belum pernah dipakai (bukan login pertama) dikirimi email peringatan
+func (s *UsecaseUser) newSession(ctx context.Context, user model.User, login *model.Login) (*model.Login, error) {
+	refresh, err := utils.NewOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	deviceName := strings.TrimSpace(login.DeviceName)
+	if deviceName == "" {
+		deviceName = utils.DeviceName(login.UserAgent)
+	}
+	session := entity.Session{
+		ID:         uuid.New().String(),
+		UserID:     user.ID,
+		DeviceName: truncate(deviceName, 100),
+		UserAgent:  truncate(login.UserAgent, 255),
+		IP:         login.IP,
+		ExpiresAt:  time.Now().Add(s.refreshTTL()),
+	}
+
+	total, sameDevice, err := s.sessionRepo.CountUserSessions(ctx, user.ID, session.UserAgent)
+	if err != nil {
+		// cek perangkat gagal tidak menggagalkan login, email saja yang tidak dikirim
+		s.log.Warnf("Gagal mengecek perangkat login user %s: %v", user.ID, err)
+	}
+	perangkatBaru := err == nil && total > 0 && sameDevice == 0
+
+	if err := s.sessionRepo.CreateSession(ctx, &session, utils.HashToken(refresh)); err != nil {
+		return nil, err
+	}
+	if perangkatBaru {
+		go s.sendNewDeviceEmail(user.Email, session)
+	}
+
+	token, err := s.jwt.GenerateAccessToken(&user, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Login{
+		Token:        token,
+		RefreshToken: refresh,
+		ExpiresIn:    int(s.jwt.AccessTokenTTL().Seconds()),
+	}, nil
+}
+
+// Tukar refresh token dengan pasangan token baru. Refresh token yang sudah pernah dipakai
+// dianggap bocor, seluruh sesinya dicabut
+func (s *UsecaseUser) RefreshToken(ctx context.Context, refreshToken string) (*model.Login, error) {
+	if refreshToken == "" {
+		return nil, utils.ErrRefreshToken
+	}
+	oldHash := utils.HashToken(refreshToken)
+
+	rt, err := s.sessionRepo.GetRefreshToken(ctx, oldHash)
+	if err != nil {
+		return nil, err
+	}
+	if rt.UsedAt != nil {
+		return nil, s.revokeReuse(ctx, rt)
+	}
+	now := time.Now()
+	if rt.SessionRevokedAt != nil || rt.ExpiresAt.Before(now) || rt.SessionExpiresAt.Before(now) {
+		return nil, utils.ErrRefreshToken
+	}
+
+	user, err := s.userRepo.GetUserID(ctx, rt.UserID)
+	if err != nil || !user.IsActive {
+		return nil, utils.ErrRefreshToken
+	}
+
+	refresh, err := utils.NewOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	err = s.sessionRepo.RotateRefreshToken(ctx, oldHash, utils.HashToken(refresh), rt.SessionID, now.Add(s.refreshTTL()))
+	if errors.Is(err, utils.ErrRefreshReuse) {
+		return nil, s.revokeReuse(ctx, rt)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// role diambil ulang dari database, perubahan role langsung berlaku setelah refresh
+	token, err := s.jwt.GenerateAccessToken(&model.User{ID: user.ID, Email: user.Email, Role: user.Role}, rt.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Login{
+		Token:        token,
+		RefreshToken: refresh,
+		ExpiresIn:    int(s.jwt.AccessTokenTTL().Seconds()),
+	}, nil
+}
+
+func (s *UsecaseUser) revokeReuse(ctx context.Context, rt entity.RefreshToken) error {
+	s.log.Warnf("Refresh token dipakai ulang, sesi %s milik user %s dicabut", rt.SessionID, rt.UserID)
+	if _, err := s.sessionRepo.RevokeSession(ctx, rt.UserID, rt.SessionID, constant.RevokeReuse); err != nil {
+		return err
+	}
+	return utils.ErrRefreshReuse
+}
+
+func (s *UsecaseUser) Logout(ctx context.Context, userID, sessionID string) error {
+	if sessionID == "" {
+		return utils.ErrSessionRevoked
+	}
+	_, err := s.sessionRepo.RevokeSession(ctx, userID, sessionID, constant.RevokeLogout)
+	return err
+}
+
+func (s *UsecaseUser) GetSessions(ctx context.Context, userID, currentSessionID string) ([]model.Session, error) {
+	sessions, err := s.sessionRepo.GetActiveSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []model.Session{}
+	for _, v := range sessions {
+		res = append(res, model.Session{
+			ID:         v.ID,
+			DeviceName: v.DeviceName,
+			UserAgent:  v.UserAgent,
+			IP:         v.IP,
+			CreatedAt:  v.CreatedAt,
+			LastUsedAt: v.LastUsedAt,
+			Current:    v.ID == currentSessionID,
+		})
+	}
+	return res, nil
+}
+
+// Cabut sesi milik user sendiri, sesi user lain dianggap tidak ditemukan
+func (s *UsecaseUser) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	ok, err := s.sessionRepo.RevokeSession(ctx, userID, sessionID, constant.RevokeUser)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return utils.ErrSessionNotFound
+	}
+	return nil
+}
+
+func (s *UsecaseUser) sendNewDeviceEmail(email string, session entity.Session) {
+	data := map[string]interface{}{
+		"Device": session.DeviceName,
+		"IP":     session.IP,
+		"Waktu":  time.Now().Format("02 Jan 2006, 15:04:05 MST"),
+	}
+
+	t, err := template.ParseFiles("./static/body.newdevice.html")
+	if err != nil {
+		s.log.Errorf("gagal load template email perangkat baru: %v", err)
+		return
+	}
+	var body bytes.Buffer
+	if err := t.Execute(&body, data); err != nil {
+		s.log.Errorf("gagal membuat email perangkat baru: %v", err)
+		return
+	}
+
+	if err := s.m.SendMail(email, SubjectNewDevice, body.String(), data); err != nil {
+		s.log.Errorf("gagal kirim email perangkat baru: %v", err)
+	}
+}
+
+func (s *UsecaseUser) LogoutAll(ctx context.Context, userID string) (int64, error) {
+	return s.sessionRepo.RevokeAllSessions(ctx, userID, constant.RevokeLogoutAll)
+}
+
+// Dipakai middleware auth di setiap request
+func (s *UsecaseUser) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	return s.sessionRepo.IsSessionActive(ctx, sessionID)
+}
+
+func (s *UsecaseUser) refreshTTL() time.Duration {
+	if s.cfg.Auth.REFRESH_TOKEN_TTL > 0 {
+		return s.cfg.Auth.REFRESH_TOKEN_TTL
+	}
+	return time.Duration(constant.RefreshTokenTTL) * 24 * time.Hour
+}
+
+// Potong per karakter (bukan byte) supaya UTF-8 tidak terbelah, VARCHAR(n) juga menghitung karakter
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		n    int
+		want string
+	}{
+		{"pendek", "curl/8.0", 255, "curl/8.0"},
+		{"ascii", "Mozilla/5.0", 7, "Mozilla"},
+		{"non-ascii", "Browser Ünïcödé 日本語", 18, "Browser Ünïcödé 日本"},
+		{"pas", "日本語", 3, "日本語"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncate(tt.in, tt.n)
+			if got != tt.want {
+				t.Errorf("truncate(%q, %d) = %q, mau %q", tt.in, tt.n, got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("hasil bukan UTF-8 valid: %q", got)
+			}
+		})
+	}
+
+	// user agent panjang dengan karakter multi-byte tepat di batas byte 255
+	ua := strings.Repeat("a", 254) + "é" + strings.Repeat("b", 10)
+	got := truncate(ua, 255)
+	if !utf8.ValidString(got) || utf8.RuneCountInString(got) != 255 {
+		t.Errorf("truncate user agent = %d karakter, valid=%v", utf8.RuneCountInString(got), utf8.ValidString(got))
+	}
+}
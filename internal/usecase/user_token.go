@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"html/template"
+	"proyek1/constant"
+	"proyek1/internal/entity"
+	"proyek1/utils"
+	"strings"
+	"time"
+)
+
+// Token sekali pakai untuk link aktivasi, reset password dan ganti email.
+// Yang disimpan hanya hash-nya, token mentah cuma dikirim ke user
+func (s *UsecaseUser) issueToken(ctx context.Context, userID, purpose, payload string, ttl time.Duration) (string, error) {
+	token, err := utils.NewOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	err = s.userRepo.CreateUserToken(ctx, &entity.UserToken{
+		TokenHash: utils.HashToken(token),
+		UserID:    userID,
+		Purpose:   purpose,
+		Payload:   payload,
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Kirim link konfirmasi ke email baru, email baru berlaku setelah link dibuka
+func (s *UsecaseUser) ChangeEmail(ctx context.Context, userID, email string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return errors.New("email tidak boleh kosong")
+	}
+	if !utils.ValidasiEmail(email) {
+		return errors.New("format email tidak benar")
+	}
+
+	user, err := s.userRepo.GetUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Email == email {
+		return errors.New("email baru sama dengan email sekarang")
+	}
+	// hanya "tidak ditemukan" yang berarti email masih bebas, error lain (database) dikembalikan
+	_, err = s.userRepo.GetUserByEmail(ctx, email)
+	switch {
+	case err == nil:
+		return utils.ErrEmailTaken
+	case !errors.Is(err, sql.ErrNoRows) && !errors.Is(err, utils.ErrIDNotFound):
+		return err
+	}
+
+	token, err := s.issueToken(ctx, user.ID, constant.PurposeChangeEmail, email, constant.ChangeEmailTTL*time.Minute)
+	if err != nil {
+		return err
+	}
+	link := fmt.Sprintf(`%s/confirm-email?token=%s`, s.cfg.URL_Server, token)
+
+	go func() {
+		if err := s.sendChangeEmail(user.Username, email, link); err != nil {
+			s.log.Errorf("gagal kirim email konfirmasi ganti email: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+func (s *UsecaseUser) ConfirmChangeEmail(ctx context.Context, token string) error {
+	if token == "" {
+		return utils.ErrPurposeToken
+	}
+
+	_, err := s.userRepo.UseUserToken(ctx, utils.HashToken(token), constant.PurposeChangeEmail, nil)
+	return err
+}
+
+func (s *UsecaseUser) sendChangeEmail(name, email, link string) error {
+	data := map[string]interface{}{
+		"Name":  name,
+		"Email": email,
+		"Link":  link,
+	}
+
+	t, err := template.ParseFiles("./static/body.changeemail.html")
+	if err != nil {
+		return fmt.Errorf("gagal load template email: %w", err)
+	}
+
+	var body bytes.Buffer
+	if err := t.Execute(&body, data); err != nil {
+		return fmt.Errorf("failed to execute email template: %w", err)
+	}
+
+	return s.m.SendMail(email, SubjectChangeEmail, body.String(), data)
+}
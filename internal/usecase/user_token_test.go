@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"proyek1/config"
+	"proyek1/internal/entity"
+	"proyek1/utils"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Repo user palsu, hanya method yang dipakai ChangeEmail yang diisi
+type fakeUserRepo struct {
+	RepositoryUserInterface
+	byEmailErr error
+	tokens     []entity.UserToken
+}
+
+func (r *fakeUserRepo) GetUserID(ctx context.Context, id string) (entity.User, error) {
+	return entity.User{ID: id, Email: "lama@mail.com", Username: "budi"}, nil
+}
+
+func (r *fakeUserRepo) GetUserByEmail(ctx context.Context, email string) (entity.User, error) {
+	if r.byEmailErr != nil {
+		return entity.User{}, r.byEmailErr
+	}
+	return entity.User{ID: "user-lain", Email: email}, nil
+}
+
+func (r *fakeUserRepo) CreateUserToken(ctx context.Context, t *entity.UserToken) error {
+	r.tokens = append(r.tokens, *t)
+	return nil
+}
+
+type fakeMailer struct {
+	mu   sync.Mutex
+	sent []string
+}
+
+func (m *fakeMailer) SendMail(emailReceiver string, subject, templatePath string, data any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, emailReceiver)
+	return nil
+}
+
+func TestChangeEmail(t *testing.T) {
+	dbErr := errors.New("koneksi database putus")
+	tests := []struct {
+		name       string
+		email      string
+		byEmailErr error
+		wantErr    error
+		wantToken  bool
+	}{
+		{name: "email bebas", email: "baru@mail.com", byEmailErr: sql.ErrNoRows, wantToken: true},
+		{name: "email bebas ErrIDNotFound", email: "baru@mail.com", byEmailErr: utils.ErrIDNotFound, wantToken: true},
+		{name: "email sudah dipakai", email: "baru@mail.com", wantErr: utils.ErrEmailTaken},
+		{name: "error database bukan berarti bebas", email: "baru@mail.com", byEmailErr: dbErr, wantErr: dbErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := logrus.New()
+			log.SetOutput(io.Discard)
+			repo := &fakeUserRepo{byEmailErr: tt.byEmailErr}
+			uc := NewUserUsecase(nil, repo, nil, log, &config.Config{URL_Server: "https://api.example.com"}, &fakeMailer{})
+
+			err := uc.ChangeEmail(context.Background(), "user-1", tt.email)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, mau %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("ChangeEmail: %v", err)
+			}
+
+			if got := len(repo.tokens) == 1; got != tt.wantToken {
+				t.Fatalf("token dibuat = %v, mau %v", got, tt.wantToken)
+			}
+			if tt.wantToken {
+				tok := repo.tokens[0]
+				if tok.UserID != "user-1" || tok.Payload != tt.email || tok.TokenHash == "" {
+					t.Errorf("token = %+v", tok)
+				}
+			}
+		})
+	}
+}
@@ -1,13 +1,23 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
 	"proyek1/app"
 	"proyek1/config"
 	"proyek1/db/migrations"
+	"proyek1/internal/repository"
 	"proyek1/utils/gmaps"
 	"proyek1/utils/mailer"
+	"proyek1/utils/places"
+	"proyek1/utils/routing"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -17,6 +27,10 @@ func main() {
 	serve := gin.Default()
 	cfg := config.EnvFile()
 	logger := logrus.New()
+	// IP untuk rate limit /photo dan sesi login, X-Forwarded-For hanya dipercaya dari proxy di TRUSTED_PROXIES
+	if err := serve.SetTrustedProxies(cfg.Server.TRUSTED_PROXIES); err != nil {
+		logger.Fatal("TRUSTED_PROXIES tidak valid:", err)
+	}
 	// Koneksi ke database
 	db, err := config.InitDatabase(*cfg)
 	if err != nil {
@@ -31,26 +45,49 @@ func main() {
 	}
 
 	// Inisialisasi JWT (pakai secret dari env)
-	jwt := config.NewJWT(logger)
+	jwt := config.NewJWT(cfg, logger)
+	photo := config.NewPhotoSigner(cfg, logger)
 	//
 	mail := mailer.NewMail(cfg.SMTP)
 	//
-	maps := gmaps.NewMail(cfg.Gmaps)
+	gm := gmaps.NewMail(cfg.Gmaps, logger)
+	// urutan dari luar: cache memory/db -> cache foto -> meter & budget -> Google
+	meter := gmaps.NewMeter(&gm, cfg.GmapsQuota, repository.NewGmapsUsageRepository(db, logger), logger)
+	maps := gmaps.NewCache(gmaps.NewPhotoCache(meter, cfg.Photo, logger), cfg.GmapsCache, repository.NewGmapsCacheRepository(db, logger), logger)
+	place := places.NewProvider(cfg.Places, maps, logger)
+	route := routing.NewProvider(cfg.Routing, maps, logger)
 	// Jalankan Bootstrap
 	bootstrap := &app.BootstrapConfig{
-		App:  serve,
-		DB:   db,
-		Log:  logger,
-		JWT:  jwt,
-		Cfg:  cfg,
-		M:    &mail,
-		Maps: &maps,
+		App:    serve,
+		DB:     db,
+		Log:    logger,
+		JWT:    jwt,
+		Cfg:    cfg,
+		M:      &mail,
+		Maps:   maps,
+		Places: place,
+		Route:  route,
+		Photo:  photo,
 	}
 	app.App(bootstrap)
 
-	// Jalankan server
-	err = serve.Run(":8081")
-	if err != nil {
-		fmt.Println("Server tidak bisa dijalankan:", err)
+	// Jalankan server, berhenti dengan rapi saat SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	srv := &http.Server{Addr: ":8081", Handler: serve}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Println("Server tidak bisa dijalankan:", err)
+			stop()
+		}
+	}()
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Warn("Server tidak berhenti dengan rapi:", err)
 	}
+	// simpan hitungan panggilan gmaps yang belum ditulis
+	meter.Close()
 }
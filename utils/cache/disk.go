@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"container/list"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DiskLRU menyimpan file di satu folder dengan batas total ukuran,
+// file yang paling lama tidak dibaca dihapus duluan
+type DiskLRU struct {
+	mu       sync.Mutex
+	dir      string
+	maxBytes int64
+	total    int64
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+type diskItem struct {
+	key  string
+	size int64
+}
+
+// Isi folder yang sudah ada dibaca ulang saat start, urutan LRU diambil dari mtime
+func NewDiskLRU(dir string, maxBytes int64) (*DiskLRU, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	d := &DiskLRU{
+		dir:      dir,
+		maxBytes: maxBytes,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	type file struct {
+		key     string
+		size    int64
+		modTime time.Time
+	}
+	var files []file
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, file{key: e.Name(), size: info.Size(), modTime: info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, f := range files {
+		d.items[f.key] = d.ll.PushFront(&diskItem{key: f.key, size: f.size})
+		d.total += f.size
+	}
+	d.evict()
+	return d, nil
+}
+
+func (d *DiskLRU) Get(key string) ([]byte, bool) {
+	d.mu.Lock()
+	el, ok := d.items[key]
+	if ok {
+		d.ll.MoveToFront(el)
+	}
+	d.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	path := filepath.Join(d.dir, key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		d.remove(key)
+		return nil, false
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return data, true
+}
+
+// Ditulis ke file sementara lalu rename, jadi pembaca tidak pernah dapat file setengah jadi
+func (d *DiskLRU) Set(key string, data []byte) error {
+	size := int64(len(data))
+	if d.maxBytes > 0 && size > d.maxBytes {
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(d.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(d.dir, key)); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if el, ok := d.items[key]; ok {
+		item := el.Value.(*diskItem)
+		d.total += size - item.size
+		item.size = size
+		d.ll.MoveToFront(el)
+	} else {
+		d.items[key] = d.ll.PushFront(&diskItem{key: key, size: size})
+		d.total += size
+	}
+	d.evict()
+	return nil
+}
+
+func (d *DiskLRU) remove(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if el, ok := d.items[key]; ok {
+		d.total -= el.Value.(*diskItem).size
+		d.ll.Remove(el)
+		delete(d.items, key)
+	}
+}
+
+// Dipanggil dengan mu terkunci
+func (d *DiskLRU) evict() {
+	if d.maxBytes <= 0 {
+		return
+	}
+	for d.total > d.maxBytes && d.ll.Len() > 0 {
+		oldest := d.ll.Back()
+		item := oldest.Value.(*diskItem)
+		d.ll.Remove(oldest)
+		delete(d.items, item.key)
+		d.total -= item.size
+		os.Remove(filepath.Join(d.dir, item.key))
+	}
+}
@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Group menggabungkan pemanggilan dengan key yang sama yang berjalan bersamaan,
+// jadi cuma satu request yang benar-benar jalan dan hasilnya dibagi ke semua pemanggil
+type Group struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+type call struct {
+	done    chan struct{}
+	val     interface{}
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+func (g *Group) Do(key string, fn func() (interface{}, error)) (interface{}, error) {
+	return g.DoContext(context.Background(), key, func(context.Context) (interface{}, error) {
+		return fn()
+	})
+}
+
+// Sama seperti Do, tapi fn dapat context sendiri yang baru dibatalkan kalau semua pemanggil
+// yang menunggu sudah batal. Pemanggil yang batal langsung dapat ctx.Err()
+func (g *Group) DoContext(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*call)
+	}
+	c, ok := g.calls[key]
+	if ok {
+		c.waiters++
+	} else {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		c = &call{done: make(chan struct{}), waiters: 1, cancel: cancel}
+		g.calls[key] = c
+		go g.run(callCtx, key, c, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.val, c.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		c.waiters--
+		if c.waiters == 0 {
+			// pemanggil berikutnya mulai request baru, bukan ikut hasil yang dibatalkan
+			c.cancel()
+			if g.calls[key] == c {
+				delete(g.calls, key)
+			}
+		}
+		g.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+func (g *Group) run(ctx context.Context, key string, c *call, fn func(ctx context.Context) (interface{}, error)) {
+	defer func() {
+		// fn jalan di goroutine sendiri, panic jangan sampai mematikan server
+		if r := recover(); r != nil {
+			c.val, c.err = nil, fmt.Errorf("panic: %v", r)
+		}
+		g.mu.Lock()
+		if g.calls[key] == c {
+			delete(g.calls, key)
+		}
+		g.mu.Unlock()
+		c.cancel()
+		close(c.done)
+	}()
+	c.val, c.err = fn(ctx)
+}
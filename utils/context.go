@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"context"
+	"proyek1/internal/model"
+)
+
+type ctxKey string
+
+const (
+	userCtxKey    ctxKey = "auth"
+	sessionCtxKey ctxKey = "places_session"
+	freshCtxKey   ctxKey = "fresh_data"
+)
+
+// Data user dari token juga disimpan di context request, supaya bisa dibaca di luar gin (contoh metering gmaps)
+func WithUser(ctx context.Context, user *model.User) context.Context {
+	return context.WithValue(ctx, userCtxKey, user)
+}
+
+func UserFromContext(ctx context.Context) (*model.User, bool) {
+	user, ok := ctx.Value(userCtxKey).(*model.User)
+	return user, ok && user != nil
+}
+
+// Session token Places Autocomplete dari client, ikut dikirim ke autocomplete dan details
+// supaya Google menghitungnya sebagai satu sesi
+func WithPlacesSession(ctx context.Context, token string) context.Context {
+	if token == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, sessionCtxKey, token)
+}
+
+func PlacesSessionFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(sessionCtxKey).(string)
+	return token
+}
+
+// Minta data langsung dari Google/OSM tanpa lewat cache (contoh resync tempat),
+// hasilnya tetap disimpan ke cache
+func WithFreshData(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshCtxKey, true)
+}
+
+func FreshDataFromContext(ctx context.Context) bool {
+	fresh, _ := ctx.Value(freshCtxKey).(bool)
+	return fresh
+}
@@ -1,6 +1,10 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"regexp"
@@ -52,6 +56,20 @@ func ValidatePassword(password string) (string, error) {
 	return password, nil
 }
 
+// Token acak untuk refresh token dan token sekali pakai, yang disimpan di database hanya hash-nya
+func NewOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 func FormatJam(jam string) string {
 	if len(jam) < 4 {
 		return fmt.Sprint("kurang")
@@ -78,10 +96,3 @@ func IsAdmin(role string) bool {
 	}
 	return true
 }
-
-func IsForgot(role string) bool {
-	if role != "forgot" {
-		return false
-	}
-	return true
-}
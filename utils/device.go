@@ -0,0 +1,57 @@
+package utils
+
+import "strings"
+
+// Tebak nama perangkat dari user agent, contoh "Chrome di Windows".
+// Urutan pengecekan penting: UA Edge dan Opera juga mengandung "Chrome", UA Chrome juga mengandung "Safari"
+func DeviceName(userAgent string) string {
+	if userAgent == "" {
+		return "Perangkat tidak dikenal"
+	}
+
+	browser := ""
+	for _, b := range []struct{ key, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"SamsungBrowser/", "Samsung Internet"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"okhttp", "Aplikasi Android"},
+		{"Dart/", "Aplikasi"},
+		{"PostmanRuntime", "Postman"},
+		{"curl/", "curl"},
+	} {
+		if strings.Contains(userAgent, b.key) {
+			browser = b.name
+			break
+		}
+	}
+
+	os := ""
+	for _, o := range []struct{ key, name string }{
+		{"iPhone", "iPhone"},
+		{"iPad", "iPad"},
+		{"Android", "Android"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	} {
+		if strings.Contains(userAgent, o.key) {
+			os = o.name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && os != "":
+		return browser + " di " + os
+	case browser != "":
+		return browser
+	case os != "":
+		return os
+	default:
+		return "Perangkat tidak dikenal"
+	}
+}
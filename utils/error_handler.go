@@ -20,13 +20,71 @@ func ConverResponse(err error) int {
 		return http.StatusBadRequest // 400
 	case ErrOtpExpire, ErrOtpNotMatch:
 		return http.StatusUnauthorized // 401
-	case ErrIDNotFound:
+	case ErrRefreshToken, ErrRefreshReuse, ErrSessionRevoked, ErrPurposeToken:
+		return http.StatusUnauthorized // 401
+	case ErrTravelMode, ErrRouteModifier, ErrTrafficAware, ErrDepartureTime, ErrRouteAlternates, ErrPhotoSize, ErrUsageRange:
+		return http.StatusBadRequest // 400
+	case ErrSearchLocation, ErrSearchRadius, ErrSearchRegion, ErrSearchType, ErrSearchLanguage, ErrPageToken, ErrSessionToken:
+		return http.StatusBadRequest // 400
+	case ErrRegionProvince, ErrRegionRegency, ErrResyncLimit, ErrClosedParam:
+		return http.StatusBadRequest // 400
+	case ErrPhotoSignature, ErrPhotoExpired:
+		return http.StatusForbidden // 403
+	case ErrTooManyRequest, ErrQuotaExceeded:
+		return http.StatusTooManyRequests // 429
+	case ErrGmapsUnavailable:
+		return http.StatusServiceUnavailable // 503
+	case ErrAutocomplete:
+		return http.StatusNotImplemented // 501
+	case ErrGmapsZeroResults, ErrGmapsNotFound:
+		return http.StatusNotFound // 404
+	case ErrGmapsInvalidRequest:
+		return http.StatusBadRequest // 400
+	case ErrGmapsOverQueryLimit:
+		return http.StatusTooManyRequests // 429
+	case ErrGmapsRequestDenied, ErrGmapsUnknown:
+		return http.StatusBadGateway // 502
+	case ErrIDNotFound, ErrSessionNotFound:
 		return http.StatusNotFound // 404
 	default:
 		return http.StatusInternalServerError
 	}
 }
 
+// Kode error yang stabil untuk client, pesan boleh berubah tapi kode tidak
+func ErrorCode(err error) string {
+	switch err {
+	case ErrGmapsZeroResults:
+		return "GMAPS_ZERO_RESULTS"
+	case ErrGmapsNotFound:
+		return "GMAPS_NOT_FOUND"
+	case ErrGmapsInvalidRequest:
+		return "GMAPS_INVALID_REQUEST"
+	case ErrGmapsOverQueryLimit:
+		return "GMAPS_OVER_QUERY_LIMIT"
+	case ErrGmapsRequestDenied:
+		return "GMAPS_REQUEST_DENIED"
+	case ErrGmapsUnknown:
+		return "GMAPS_UNKNOWN_ERROR"
+	case ErrGmapsUnavailable:
+		return "GMAPS_UNAVAILABLE"
+	case ErrQuotaExceeded:
+		return "GMAPS_QUOTA_EXCEEDED"
+	case ErrPageToken:
+		return "INVALID_PAGE_TOKEN"
+	case ErrRefreshToken:
+		return "INVALID_REFRESH_TOKEN"
+	case ErrRefreshReuse:
+		return "REFRESH_TOKEN_REUSED"
+	case ErrSessionRevoked:
+		return "SESSION_REVOKED"
+	case ErrPurposeToken:
+		return "INVALID_TOKEN"
+	default:
+		return ""
+	}
+}
+
 func HandleEchoError(err error) (int, string) {
 	if _, ok := err.(*gin.Error); ok {
 		return http.StatusBadRequest, BadInput
@@ -25,6 +25,57 @@ var (
 	ErrOtpExpire            = errors.New("otp telah expired")
 	ErrOtpNotMatch          = errors.New("otp salah")
 
+	// Sesi login
+	ErrRefreshToken    = errors.New("refresh token tidak valid atau sudah kedaluwarsa, silakan login ulang")
+	ErrRefreshReuse    = errors.New("refresh token sudah pernah dipakai, sesi dicabut demi keamanan, silakan login ulang")
+	ErrSessionRevoked  = errors.New("sesi sudah berakhir atau logout, silakan login ulang")
+	ErrSessionNotFound = errors.New("sesi tidak ditemukan atau sudah berakhir")
+	ErrPurposeToken    = errors.New("token tidak valid, sudah dipakai atau kedaluwarsa")
+
 	//
 	ErrIDNotFound = errors.New("Id tidak ditemukan atau kosong")
+
+	// Route
+	ErrTravelMode      = errors.New("travel mode harus DRIVE, TWO_WHEELER, WALK, BICYCLE atau TRANSIT")
+	ErrRouteModifier   = errors.New("avoid tol, jalan raya dan ferry hanya untuk DRIVE dan TWO_WHEELER")
+	ErrTrafficAware    = errors.New("rute berdasarkan lalu lintas hanya untuk DRIVE dan TWO_WHEELER")
+	ErrDepartureTime   = errors.New("format departure time harus RFC3339 dan tidak boleh di masa lalu")
+	ErrRouteAlternates = errors.New("jumlah rute alternatif harus 0 sampai 3")
+
+	// Pencarian tempat
+	ErrSearchLocation = errors.New("lat dan lng harus dikirim berdua, lat -90 sampai 90 dan lng -180 sampai 180")
+	ErrSearchRadius   = errors.New("radius harus angka 1 sampai 50000 meter dan hanya bisa dipakai bersama lat/lng")
+	ErrSearchRegion   = errors.New("region harus kode negara 2 huruf, contoh id")
+	ErrSearchType     = errors.New("type hanya boleh huruf kecil dan underscore, contoh tourist_attraction")
+	ErrSearchLanguage = errors.New("language harus id atau en")
+	ErrSessionToken   = errors.New("session harus 1 sampai 64 karakter huruf, angka, - atau _")
+	ErrAutocomplete   = errors.New("autocomplete hanya tersedia dengan google maps")
+	ErrPageToken      = errors.New("page token tidak valid atau sudah kedaluwarsa, ulangi pencarian dari halaman pertama")
+
+	// Wilayah
+	ErrRegionProvince = errors.New("province tidak dikenal, gunakan kode atau nama provinsi")
+	ErrRegionRegency  = errors.New("regency hanya bisa dipakai bersama province")
+
+	// Resync tempat
+	ErrResyncLimit = errors.New("limit resync harus angka 1 sampai 100")
+	ErrClosedParam = errors.New("closed harus true atau false")
+
+	// Foto
+	ErrPhotoSize      = errors.New("maxwidth/maxheight harus salah satu dari 100, 200, 400, 800 atau 1600")
+	ErrPhotoSignature = errors.New("url foto tidak valid")
+	ErrPhotoExpired   = errors.New("url foto sudah kedaluwarsa")
+	ErrTooManyRequest = errors.New("terlalu banyak request, coba lagi nanti")
+
+	// Gmaps
+	ErrQuotaExceeded    = errors.New("batas penggunaan google maps sudah tercapai, coba lagi nanti")
+	ErrGmapsUnavailable = errors.New("layanan google maps sedang tidak tersedia, coba lagi nanti")
+
+	// Status dari Google (Places, Geocoding, Routes)
+	ErrGmapsZeroResults    = errors.New("tempat atau rute tidak ditemukan")
+	ErrGmapsNotFound       = errors.New("place id tidak ditemukan di google maps")
+	ErrGmapsInvalidRequest = errors.New("permintaan ke google maps tidak valid")
+	ErrGmapsOverQueryLimit = errors.New("batas request google maps terlampaui, coba lagi nanti")
+	ErrGmapsRequestDenied  = errors.New("permintaan ditolak google maps, periksa konfigurasi api key")
+	ErrGmapsUnknown        = errors.New("terjadi kesalahan pada google maps")
+	ErrUsageRange          = errors.New("format from/to harus YYYY-MM-DD, from tidak boleh setelah to dan maksimal 1 tahun")
 )
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Durasi format Routes API ("4800s") jadi "1 jam 20 menit"
+func FormatDurasi(durasi string) string {
+	d, err := time.ParseDuration(durasi)
+	if err != nil {
+		return durasi
+	}
+	menitTotal := int(d.Round(time.Minute).Minutes())
+	if menitTotal < 1 {
+		return "kurang dari 1 menit"
+	}
+
+	jam, menit := menitTotal/60, menitTotal%60
+	switch {
+	case jam == 0:
+		return fmt.Sprintf("%d menit", menit)
+	case menit == 0:
+		return fmt.Sprintf("%d jam", jam)
+	default:
+		return fmt.Sprintf("%d jam %d menit", jam, menit)
+	}
+}
+
+// Jarak meter jadi "850 m" atau "3,4 km"
+func FormatJarak(meter int) string {
+	if meter < 1000 {
+		return fmt.Sprintf("%d m", meter)
+	}
+	km := float64(meter) / 1000
+	if km >= 100 {
+		return fmt.Sprintf("%.0f km", km)
+	}
+	s := strconv.FormatFloat(km, 'f', 1, 64)
+	s = strings.TrimSuffix(s, ".0")
+	return strings.Replace(s, ".", ",", 1) + " km"
+}
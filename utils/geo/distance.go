@@ -0,0 +1,45 @@
+package geo
+
+import "math"
+
+const EarthRadius = 6371000.0 // meter
+
+type BBox struct {
+	MinLat float64
+	MinLng float64
+	MaxLat float64
+	MaxLng float64
+}
+
+func (b BBox) Contains(lat, lng float64) bool {
+	return lat >= b.MinLat && lat <= b.MaxLat && lng >= b.MinLng && lng <= b.MaxLng
+}
+
+func (b BBox) Center() (float64, float64) {
+	return (b.MinLat + b.MaxLat) / 2, (b.MinLng + b.MaxLng) / 2
+}
+
+// Jarak garis lurus di permukaan bumi dalam meter
+func Haversine(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadius * math.Asin(math.Sqrt(a))
+}
+
+// Kotak yang menutupi lingkaran radius meter di sekitar titik
+func BBoxAround(lat, lng, radius float64) BBox {
+	dLat := radius / EarthRadius * 180 / math.Pi
+	dLng := dLat / math.Max(math.Cos(toRad(lat)), 1e-6)
+	return BBox{
+		MinLat: math.Max(lat-dLat, -90),
+		MaxLat: math.Min(lat+dLat, 90),
+		MinLng: math.Max(lng-dLng, -180),
+		MaxLng: math.Min(lng+dLng, 180),
+	}
+}
+
+func toRad(deg float64) float64 {
+	return deg * math.Pi / 180
+}
@@ -0,0 +1,60 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHaversine(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lng1, lat2, lng2 float64
+		want, tolerance        float64
+	}{
+		{"titik sama", -6.17539, 106.82715, -6.17539, 106.82715, 0, 0.001},
+		{"1 derajat di khatulistiwa", 0, 0, 0, 1, 111194.93, 0.5},
+		{"1 derajat lintang", 10, 20, 11, 20, 111194.93, 0.5},
+		{"Monas ke Blok M", -6.17539, 106.82715, -6.2446, 106.8003, 8246, 20},
+		{"melewati garis tanggal", 0, 179.5, 0, -179.5, 111194.93, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Haversine(tt.lat1, tt.lng1, tt.lat2, tt.lng2)
+			if math.Abs(got-tt.want) > tt.tolerance {
+				t.Errorf("Haversine = %.2f, mau %.2f ± %.2f", got, tt.want, tt.tolerance)
+			}
+			if back := Haversine(tt.lat2, tt.lng2, tt.lat1, tt.lng1); math.Abs(back-got) > 1e-6 {
+				t.Errorf("tidak simetris: %.4f vs %.4f", got, back)
+			}
+		})
+	}
+}
+
+func TestBBoxAround(t *testing.T) {
+	lat, lng, radius := -6.17539, 106.82715, 5000.0
+	b := BBoxAround(lat, lng, radius)
+
+	if !b.Contains(lat, lng) {
+		t.Fatalf("bbox %+v tidak berisi titik pusat", b)
+	}
+	cLat, cLng := b.Center()
+	if math.Abs(cLat-lat) > 1e-9 || math.Abs(cLng-lng) > 1e-9 {
+		t.Errorf("pusat bbox = %v,%v, mau %v,%v", cLat, cLng, lat, lng)
+	}
+	// sisi bbox berjarak radius dari pusat
+	if d := Haversine(lat, lng, b.MaxLat, lng); math.Abs(d-radius) > 1 {
+		t.Errorf("jarak ke sisi utara = %.2f, mau %.0f", d, radius)
+	}
+	if d := Haversine(lat, lng, lat, b.MaxLng); math.Abs(d-radius) > 5 {
+		t.Errorf("jarak ke sisi timur = %.2f, mau %.0f", d, radius)
+	}
+	if b.Contains(lat+0.1, lng) {
+		t.Error("titik 11 km ke utara tidak boleh masuk bbox 5 km")
+	}
+
+	// dekat kutub dibatasi ke -90..90 dan -180..180
+	polar := BBoxAround(89.99, 0, 100000)
+	if polar.MaxLat != 90 || polar.MinLng != -180 || polar.MaxLng != 180 {
+		t.Errorf("bbox dekat kutub = %+v", polar)
+	}
+}
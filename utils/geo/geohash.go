@@ -0,0 +1,120 @@
+package geo
+
+import (
+	"math"
+	"strings"
+)
+
+const base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// Encode koordinat jadi geohash dengan panjang precision karakter
+func Encode(lat, lng float64, precision int) string {
+	latMin, latMax := -90.0, 90.0
+	lngMin, lngMax := -180.0, 180.0
+
+	var sb strings.Builder
+	bit, ch := 0, 0
+	even := true
+	for sb.Len() < precision {
+		if even {
+			mid := (lngMin + lngMax) / 2
+			if lng >= mid {
+				ch = ch<<1 | 1
+				lngMin = mid
+			} else {
+				ch <<= 1
+				lngMax = mid
+			}
+		} else {
+			mid := (latMin + latMax) / 2
+			if lat >= mid {
+				ch = ch<<1 | 1
+				latMin = mid
+			} else {
+				ch <<= 1
+				latMax = mid
+			}
+		}
+		even = !even
+
+		bit++
+		if bit == 5 {
+			sb.WriteByte(base32[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return sb.String()
+}
+
+// Decode geohash jadi kotak area yang diwakilinya
+func Decode(hash string) BBox {
+	b := BBox{MinLat: -90, MaxLat: 90, MinLng: -180, MaxLng: 180}
+	even := true
+	for i := 0; i < len(hash); i++ {
+		idx := strings.IndexByte(base32, hash[i])
+		if idx < 0 {
+			return BBox{}
+		}
+		for n := 4; n >= 0; n-- {
+			bitSet := idx>>n&1 == 1
+			if even {
+				mid := (b.MinLng + b.MaxLng) / 2
+				if bitSet {
+					b.MinLng = mid
+				} else {
+					b.MaxLng = mid
+				}
+			} else {
+				mid := (b.MinLat + b.MaxLat) / 2
+				if bitSet {
+					b.MinLat = mid
+				} else {
+					b.MaxLat = mid
+				}
+			}
+			even = !even
+		}
+	}
+	return b
+}
+
+// Ukuran satu sel geohash (derajat lat, derajat lng) untuk precision tertentu
+func CellSize(precision int) (float64, float64) {
+	bits := 5 * precision
+	lngBits := (bits + 1) / 2
+	latBits := bits / 2
+	return 180 / math.Pow(2, float64(latBits)), 360 / math.Pow(2, float64(lngBits))
+}
+
+// Prefix geohash yang menutupi bbox, precision dipilih sebesar mungkin selama jumlah sel <= maxCells.
+// Return nil kalau bbox terlalu besar untuk dipersempit dengan prefix.
+func CoverBBox(b BBox, maxCells int) []string {
+	for p := 9; p >= 1; p-- {
+		h, w := CellSize(p)
+		rows := cellIndex(b.MaxLat+90, h, 180) - cellIndex(b.MinLat+90, h, 180) + 1
+		cols := cellIndex(b.MaxLng+180, w, 360) - cellIndex(b.MinLng+180, w, 360) + 1
+		if rows*cols > maxCells {
+			continue
+		}
+
+		var res []string
+		for i := cellIndex(b.MinLat+90, h, 180); i <= cellIndex(b.MaxLat+90, h, 180); i++ {
+			for j := cellIndex(b.MinLng+180, w, 360); j <= cellIndex(b.MaxLng+180, w, 360); j++ {
+				res = append(res, Encode(-90+(float64(i)+0.5)*h, -180+(float64(j)+0.5)*w, p))
+			}
+		}
+		return res
+	}
+	return nil
+}
+
+func cellIndex(v, size, max float64) int {
+	i := int(math.Floor(v / size))
+	if last := int(max/size) - 1; i > last {
+		i = last
+	}
+	if i < 0 {
+		i = 0
+	}
+	return i
+}
@@ -0,0 +1,96 @@
+package geo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		lat, lng  float64
+		precision int
+		want      string
+	}{
+		{57.64911, 10.40744, 11, "u4pruydqqvj"},
+		{-6.17539, 106.82715, 7, "qqguygv"}, // Monas
+		{0, 0, 1, "s"},
+		{-90, -180, 4, "0000"},
+	}
+	for _, tt := range tests {
+		if got := Encode(tt.lat, tt.lng, tt.precision); got != tt.want {
+			t.Errorf("Encode(%v, %v, %d) = %q, mau %q", tt.lat, tt.lng, tt.precision, got, tt.want)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	b := Decode("qqguygv")
+	if !b.Contains(-6.17539, 106.82715) {
+		t.Errorf("Decode(qqguygv) = %+v tidak berisi Monas", b)
+	}
+	h, w := CellSize(7)
+	if d := (b.MaxLat - b.MinLat) - h; d > 1e-9 || d < -1e-9 {
+		t.Errorf("tinggi sel = %v, mau %v", b.MaxLat-b.MinLat, h)
+	}
+	if d := (b.MaxLng - b.MinLng) - w; d > 1e-9 || d < -1e-9 {
+		t.Errorf("lebar sel = %v, mau %v", b.MaxLng-b.MinLng, w)
+	}
+	if got := Decode("qqa!"); got != (BBox{}) {
+		t.Errorf("karakter tidak valid harus BBox kosong, dapat %+v", got)
+	}
+}
+
+func TestCoverBBox(t *testing.T) {
+	tests := []struct {
+		name      string
+		bbox      BBox
+		maxCells  int
+		wantNil   bool
+		wantCells int
+		wantLen   int
+	}{
+		{name: "titik", bbox: BBox{MinLat: -6.17539, MinLng: 106.82715, MaxLat: -6.17539, MaxLng: 106.82715}, maxCells: 4, wantCells: 1, wantLen: 9},
+		{name: "Jakarta pusat", bbox: BBox{MinLat: -6.21, MinLng: 106.80, MaxLat: -6.15, MaxLng: 106.86}, maxCells: 9},
+		{name: "satu sel saja", bbox: BBox{MinLat: -6.21, MinLng: 106.80, MaxLat: -6.15, MaxLng: 106.86}, maxCells: 1},
+		{name: "seluruh dunia", bbox: BBox{MinLat: -90, MinLng: -180, MaxLat: 90, MaxLng: 180}, maxCells: 16, wantNil: true},
+		{name: "seluruh dunia 32 sel", bbox: BBox{MinLat: -90, MinLng: -180, MaxLat: 90, MaxLng: 180}, maxCells: 32, wantCells: 32, wantLen: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cells := CoverBBox(tt.bbox, tt.maxCells)
+			if tt.wantNil {
+				if cells != nil {
+					t.Fatalf("mau nil, dapat %v", cells)
+				}
+				return
+			}
+			if len(cells) == 0 || len(cells) > tt.maxCells {
+				t.Fatalf("jumlah sel = %d, maks %d", len(cells), tt.maxCells)
+			}
+			if tt.wantCells > 0 && len(cells) != tt.wantCells {
+				t.Errorf("jumlah sel = %d, mau %d", len(cells), tt.wantCells)
+			}
+			if tt.wantLen > 0 && len(cells[0]) != tt.wantLen {
+				t.Errorf("precision = %d, mau %d", len(cells[0]), tt.wantLen)
+			}
+
+			// semua titik di dalam bbox harus punya geohash dengan salah satu prefix
+			b := tt.bbox
+			for _, lat := range []float64{b.MinLat, (b.MinLat + b.MaxLat) / 2, b.MaxLat} {
+				for _, lng := range []float64{b.MinLng, (b.MinLng + b.MaxLng) / 2, b.MaxLng} {
+					h := Encode(lat, lng, 9)
+					covered := false
+					for _, c := range cells {
+						if strings.HasPrefix(h, c) {
+							covered = true
+							break
+						}
+					}
+					if !covered {
+						t.Errorf("titik %v,%v (%s) tidak tertutup %v", lat, lng, h, cells)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+// Decode encoded polyline format Google (precision 5)
+func DecodePolyline(encoded string) ([]Point, error) {
+	var points []Point
+	var lat, lng int
+	for i := 0; i < len(encoded); {
+		var dLat, dLng int
+		var err error
+		if dLat, i, err = decodeValue(encoded, i); err != nil {
+			return nil, err
+		}
+		if dLng, i, err = decodeValue(encoded, i); err != nil {
+			return nil, err
+		}
+		lat += dLat
+		lng += dLng
+		points = append(points, Point{Lat: float64(lat) / 1e5, Lng: float64(lng) / 1e5})
+	}
+	return points, nil
+}
+
+func decodeValue(encoded string, i int) (int, int, error) {
+	var result, shift int
+	for {
+		if i >= len(encoded) {
+			return 0, i, errors.New("polyline terpotong")
+		}
+		b := int(encoded[i]) - 63
+		i++
+		if b < 0 || b > 63 {
+			return 0, i, errors.New("karakter polyline tidak valid")
+		}
+		result |= (b & 0x1f) << shift
+		shift += 5
+		if b < 0x20 {
+			break
+		}
+	}
+	if result&1 != 0 {
+		return ^(result >> 1), i, nil
+	}
+	return result >> 1, i, nil
+}
+
+// Encode titik jadi encoded polyline format Google (precision 5)
+func EncodePolyline(points []Point) string {
+	var sb strings.Builder
+	var prevLat, prevLng int
+	for _, p := range points {
+		lat := int(math.Round(p.Lat * 1e5))
+		lng := int(math.Round(p.Lng * 1e5))
+		encodeValue(&sb, lat-prevLat)
+		encodeValue(&sb, lng-prevLng)
+		prevLat, prevLng = lat, lng
+	}
+	return sb.String()
+}
+
+func encodeValue(sb *strings.Builder, v int) {
+	v <<= 1
+	if v < 0 {
+		v = ^v
+	}
+	for v >= 0x20 {
+		sb.WriteByte(byte((0x20 | (v & 0x1f)) + 63))
+		v >>= 5
+	}
+	sb.WriteByte(byte(v + 63))
+}
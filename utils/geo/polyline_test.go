@@ -0,0 +1,62 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// Contoh dari dokumentasi Encoded Polyline Algorithm Google
+const googleExample = "_p~iF~ps|U_ulLnnqC_mqNvxq`@"
+
+func TestDecodePolyline(t *testing.T) {
+	points, err := DecodePolyline(googleExample)
+	if err != nil {
+		t.Fatalf("DecodePolyline: %v", err)
+	}
+	want := []Point{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+	if len(points) != len(want) {
+		t.Fatalf("jumlah titik = %d, mau %d", len(points), len(want))
+	}
+	for i, p := range points {
+		if math.Abs(p.Lat-want[i].Lat) > 1e-9 || math.Abs(p.Lng-want[i].Lng) > 1e-9 {
+			t.Errorf("titik %d = %+v, mau %+v", i, p, want[i])
+		}
+	}
+}
+
+func TestDecodePolylineError(t *testing.T) {
+	tests := map[string]string{
+		"terpotong":            "_p~iF~ps|U_",
+		"karakter tidak valid": "_p~iF ps|U",
+		"hanya lat":            "_p~iF",
+	}
+	for name, encoded := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := DecodePolyline(encoded); err == nil {
+				t.Errorf("DecodePolyline(%q) harus error", encoded)
+			}
+		})
+	}
+	if points, err := DecodePolyline(""); err != nil || len(points) != 0 {
+		t.Errorf("polyline kosong = %v, %v", points, err)
+	}
+}
+
+func TestEncodePolyline(t *testing.T) {
+	points := []Point{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+	if got := EncodePolyline(points); got != googleExample {
+		t.Errorf("EncodePolyline = %q, mau %q", got, googleExample)
+	}
+
+	// bolak-balik, presisi 5 desimal
+	route := []Point{{-6.17539, 106.82715}, {-6.1935, 106.823}, {-6.2446, 106.8003}, {0, 0}, {-89.99999, 179.99999}}
+	decoded, err := DecodePolyline(EncodePolyline(route))
+	if err != nil {
+		t.Fatalf("DecodePolyline: %v", err)
+	}
+	for i := range route {
+		if math.Abs(decoded[i].Lat-route[i].Lat) > 1e-5 || math.Abs(decoded[i].Lng-route[i].Lng) > 1e-5 {
+			t.Errorf("titik %d = %+v, mau %+v", i, decoded[i], route[i])
+		}
+	}
+}
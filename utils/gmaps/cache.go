@@ -0,0 +1,304 @@
+package gmaps
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"proyek1/config"
+	"proyek1/internal/model"
+	"proyek1/utils"
+	"proyek1/utils/cache"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultTTLSearch       = 24 * time.Hour
+	defaultTTLDetails      = 24 * time.Hour
+	defaultTTLGeocode      = 7 * 24 * time.Hour
+	defaultTTLRoute        = 5 * time.Minute
+	defaultTTLAutocomplete = time.Hour
+	// next_page_token Google kedaluwarsa dalam hitungan menit, halaman yang membawa token tidak boleh di-cache lebih lama
+	pageTokenTTL = 2 * time.Minute
+
+	cacheStoreTimeout = 2 * time.Second
+	cachePurgeEvery   = time.Hour
+	cacheStaleTTL     = time.Minute
+)
+
+// Penyimpanan cache tahap kedua (Postgres), dipakai kalau LRU memory miss.
+// GetCache harus mengembalikan error kalau key tidak ada, atau sudah expired dan allowStale false
+type CacheStore interface {
+	GetCache(ctx context.Context, key string, allowStale bool) ([]byte, time.Time, error)
+	SetCache(ctx context.Context, key, kind string, value []byte, expiresAt time.Time) error
+	PurgeCache(ctx context.Context) (int64, error)
+}
+
+type cachedGmaps struct {
+	next  GmapsInterface
+	lru   *cache.LRU
+	store CacheStore
+	group cache.Group
+	ttl   map[string]time.Duration
+	log   *logrus.Logger
+
+	purgeMu   sync.Mutex
+	lastPurge time.Time
+}
+
+// Bungkus GmapsInterface dengan cache LRU + store (boleh nil).
+// Foto dan PhotoReference tidak di-cache di sini
+func NewCache(next GmapsInterface, c config.GMAPS_CACHE, store CacheStore, log *logrus.Logger) GmapsInterface {
+	search := ttlOrDefault(c.GMAPS_TTL_SEARCH, defaultTTLSearch)
+	geocode := ttlOrDefault(c.GMAPS_TTL_GEOCODE, defaultTTLGeocode)
+	return &cachedGmaps{
+		next:  next,
+		lru:   cache.NewLRU(c.GMAPS_CACHE_SIZE),
+		store: store,
+		log:   log,
+		ttl: map[string]time.Duration{
+			EndpointSearch:       search,
+			EndpointList:         search,
+			EndpointDetails:      ttlOrDefault(c.GMAPS_TTL_DETAILS, defaultTTLDetails),
+			EndpointGeocode:      geocode,
+			EndpointReverse:      geocode,
+			EndpointRoute:        ttlOrDefault(c.GMAPS_TTL_ROUTE, defaultTTLRoute),
+			EndpointAutocomplete: ttlOrDefault(c.GMAPS_TTL_AUTOCOMPLETE, defaultTTLAutocomplete),
+		},
+	}
+}
+
+func ttlOrDefault(ttl, def time.Duration) time.Duration {
+	if ttl == 0 {
+		return def
+	}
+	return ttl
+}
+
+func (c *cachedGmaps) GmapsSearchObject(ctx context.Context, inputTempat string, opts model.SearchOptions) (model.Maps, error) {
+	return cached(ctx, c, EndpointSearch, searchKey(inputTempat, opts), func(ctx context.Context) (model.Maps, error) {
+		return c.next.GmapsSearchObject(ctx, inputTempat, opts)
+	})
+}
+
+// Halaman yang punya next_page_token hanya di-cache pageTokenTTL, setelah itu token dari Google
+// sudah kedaluwarsa dan halaman diambil ulang supaya dapat token baru. Halaman terakhir (tanpa token) pakai TTL search biasa
+func (c *cachedGmaps) GmapsSearchList(ctx context.Context, inputTempat string, opts model.SearchOptions) (model.MapsList, error) {
+	fetch := func(ctx context.Context) (model.MapsList, error) {
+		return c.next.GmapsSearchList(ctx, inputTempat, opts)
+	}
+	ttlOf := func(res model.MapsList, ttl time.Duration) time.Duration {
+		if res.NextPageToken != "" && ttl > pageTokenTTL {
+			return pageTokenTTL
+		}
+		return ttl
+	}
+	return cachedTTL(ctx, c, EndpointList, searchKey(inputTempat, opts), fetch, ttlOf)
+}
+
+// Details dengan session token autocomplete selalu dikirim ke Google: panggilan itu yang menutup sesi,
+// tanpa itu setiap ketikan autocomplete ditagih sebagai request terpisah. Hasilnya tetap mengisi cache
+func (c *cachedGmaps) GmapsSearchByPlaceID(ctx context.Context, placeID, language string) (model.MapsGetByPlaceId, error) {
+	if utils.PlacesSessionFromContext(ctx) != "" {
+		ctx = utils.WithFreshData(ctx)
+	}
+	return cached(ctx, c, EndpointDetails, searchLanguage(language)+":"+placeID, func(ctx context.Context) (model.MapsGetByPlaceId, error) {
+		return c.next.GmapsSearchByPlaceID(ctx, placeID, language)
+	})
+}
+
+// Key tidak termasuk session token, prediksi untuk ketikan yang sama tetap sama antar sesi
+func (c *cachedGmaps) Autocomplete(ctx context.Context, input string, opts model.SearchOptions) ([]model.Prediction, error) {
+	return cached(ctx, c, EndpointAutocomplete, searchKey(input, opts), func(ctx context.Context) ([]model.Prediction, error) {
+		return c.next.Autocomplete(ctx, input, opts)
+	})
+}
+
+func (c *cachedGmaps) Geocode(ctx context.Context, address string) ([]model.GeocodeResult, error) {
+	return cached(ctx, c, EndpointGeocode, normalizeQuery(address), func(ctx context.Context) ([]model.GeocodeResult, error) {
+		return c.next.Geocode(ctx, address)
+	})
+}
+
+// Koordinat dibulatkan ~1 meter supaya posisi yang hampir sama tetap kena cache
+func (c *cachedGmaps) ReverseGeocode(ctx context.Context, lat, lng float64) ([]model.GeocodeResult, error) {
+	return cached(ctx, c, EndpointReverse, fmt.Sprintf("%.5f,%.5f", lat, lng), func(ctx context.Context) ([]model.GeocodeResult, error) {
+		return c.next.ReverseGeocode(ctx, lat, lng)
+	})
+}
+
+func (c *cachedGmaps) RouteToDestination(ctx context.Context, req model.RequestRouteMaps) (*model.ResponseRouteMaps, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return c.next.RouteToDestination(ctx, req)
+	}
+	return cached(ctx, c, EndpointRoute, string(body), func(ctx context.Context) (*model.ResponseRouteMaps, error) {
+		return c.next.RouteToDestination(ctx, req)
+	})
+}
+
+func (c *cachedGmaps) PhotoReference(photoURl string) (string, error) {
+	return c.next.PhotoReference(photoURl)
+}
+
+func (c *cachedGmaps) GetPhoto(ctx context.Context, photoRef string, maxWidth, maxHeight int) (*model.PhotoFile, error) {
+	return c.next.GetPhoto(ctx, photoRef, maxWidth, maxHeight)
+}
+
+// Urutan: LRU -> store -> Google. Request bersamaan dengan key yang sama digabung lewat group,
+// hasilnya dibagi dalam bentuk JSON supaya tiap pemanggil dapat salinan sendiri.
+// Request ke Google baru dibatalkan kalau semua pemanggil yang menunggu sudah batal.
+// Dengan utils.WithFreshData cache tidak dibaca, hanya diisi ulang
+func cached[T any](ctx context.Context, c *cachedGmaps, kind, input string, fetch func(ctx context.Context) (T, error)) (T, error) {
+	return cachedTTL(ctx, c, kind, input, fetch, nil)
+}
+
+// Sama dengan cached, ttlOf (boleh nil) bisa memperpendek TTL berdasarkan isi response
+func cachedTTL[T any](ctx context.Context, c *cachedGmaps, kind, input string, fetch func(ctx context.Context) (T, error), ttlOf func(T, time.Duration) time.Duration) (T, error) {
+	ttl := c.ttl[kind]
+	if ttl < 0 {
+		return fetch(ctx)
+	}
+	key := cacheKey(kind, input)
+	ttlFor := func(data T) time.Duration {
+		if ttlOf == nil {
+			return ttl
+		}
+		return ttlOf(data, ttl)
+	}
+
+	var res T
+	if utils.FreshDataFromContext(ctx) {
+		data, err := fetch(ctx)
+		if err != nil {
+			return res, err
+		}
+		if raw, err := json.Marshal(data); err == nil {
+			dataTTL := ttlFor(data)
+			c.lru.Set(key, raw, dataTTL)
+			c.saveStore(key, kind, raw, dataTTL)
+		}
+		return data, nil
+	}
+	if raw, ok := c.lru.Get(key); ok {
+		if err := json.Unmarshal(raw, &res); err == nil {
+			return res, nil
+		}
+		c.lru.Delete(key)
+	}
+
+	val, err := c.group.DoContext(ctx, key, func(ctx context.Context) (interface{}, error) {
+		// data di store dengan format lama (sebelum struktur response berubah) dianggap miss
+		var probe T
+		if raw, ok := c.loadStore(key, false); ok && json.Unmarshal(raw, &probe) == nil {
+			return raw, nil
+		}
+
+		data, err := fetch(ctx)
+		if errors.Is(err, utils.ErrQuotaExceeded) {
+			// budget habis, pakai data lama kalau masih ada
+			if raw, ok := c.loadStore(key, true); ok && json.Unmarshal(raw, &probe) == nil {
+				return raw, nil
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		dataTTL := ttlFor(data)
+		c.lru.Set(key, raw, dataTTL)
+		c.saveStore(key, kind, raw, dataTTL)
+		return raw, nil
+	})
+	if err != nil {
+		return res, err
+	}
+	err = json.Unmarshal(val.([]byte), &res)
+	return res, err
+}
+
+func (c *cachedGmaps) loadStore(key string, allowStale bool) ([]byte, bool) {
+	if c.store == nil {
+		return nil, false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cacheStoreTimeout)
+	defer cancel()
+
+	raw, expiresAt, err := c.store.GetCache(ctx, key, allowStale)
+	if err != nil {
+		return nil, false
+	}
+	ttl := time.Until(expiresAt)
+	if ttl < cacheStaleTTL {
+		ttl = cacheStaleTTL
+	}
+	c.lru.Set(key, raw, ttl)
+	return raw, true
+}
+
+// Gagal simpan ke store cukup di-log, response tetap dikembalikan
+func (c *cachedGmaps) saveStore(key, kind string, raw []byte, ttl time.Duration) {
+	if c.store == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cacheStoreTimeout)
+	defer cancel()
+
+	if err := c.store.SetCache(ctx, key, kind, raw, time.Now().Add(ttl)); err != nil {
+		c.log.Warnf("Gagal menyimpan cache gmaps %s: %v", kind, err)
+	}
+	c.purgeExpired()
+}
+
+// Hapus row expired paling sering sekali per jam, jalan di background
+func (c *cachedGmaps) purgeExpired() {
+	c.purgeMu.Lock()
+	if time.Since(c.lastPurge) < cachePurgeEvery {
+		c.purgeMu.Unlock()
+		return
+	}
+	c.lastPurge = time.Now()
+	c.purgeMu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		n, err := c.store.PurgeCache(ctx)
+		if err != nil {
+			c.log.Warnf("Gagal menghapus cache gmaps expired: %v", err)
+			return
+		}
+		c.log.Debugf("Cache gmaps expired dihapus: %d", n)
+	}()
+}
+
+func cacheKey(kind, input string) string {
+	sum := sha1.Sum([]byte(input))
+	return kind + ":" + hex.EncodeToString(sum[:])
+}
+
+// Posisi user dibulatkan ~100 meter, untuk location bias selisih segitu tidak mengubah hasil
+// tapi cache tetap kena untuk user yang berdekatan
+func searchKey(input string, opts model.SearchOptions) string {
+	key := normalizeQuery(input) + "|" + searchLanguage(opts.Language) + "|" + opts.Region + "|" + opts.Type
+	if opts.Location != nil {
+		key += fmt.Sprintf("|%.3f,%.3f,%d", opts.Location.Latitude, opts.Location.Longitude, searchRadius(opts.Radius))
+	}
+	if opts.PageToken != "" {
+		key += "|" + opts.PageToken
+	}
+	return key
+}
+
+func normalizeQuery(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
@@ -0,0 +1,140 @@
+package gmaps
+
+import (
+	"context"
+	"errors"
+	"io"
+	"proyek1/config"
+	"proyek1/internal/model"
+	"proyek1/utils"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// GmapsInterface palsu, hanya method yang dipakai test yang diisi
+type countingGmaps struct {
+	GmapsInterface
+	mu    sync.Mutex
+	calls map[string]int
+	list  model.MapsList
+}
+
+func (g *countingGmaps) count(name string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.calls == nil {
+		g.calls = map[string]int{}
+	}
+	g.calls[name]++
+}
+
+func (g *countingGmaps) GmapsSearchList(ctx context.Context, inputTempat string, opts model.SearchOptions) (model.MapsList, error) {
+	g.count("list")
+	return g.list, nil
+}
+
+func (g *countingGmaps) GmapsSearchByPlaceID(ctx context.Context, placeID, language string) (model.MapsGetByPlaceId, error) {
+	g.count("details")
+	return model.MapsGetByPlaceId{PlaceID: placeID}, nil
+}
+
+// Store palsu yang mencatat masa berlaku tiap key
+type memoryStore struct {
+	mu      sync.Mutex
+	expires map[string]time.Time
+}
+
+func (s *memoryStore) GetCache(ctx context.Context, key string, allowStale bool) ([]byte, time.Time, error) {
+	return nil, time.Time{}, errors.New("miss")
+}
+
+func (s *memoryStore) SetCache(ctx context.Context, key, kind string, value []byte, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.expires == nil {
+		s.expires = map[string]time.Time{}
+	}
+	s.expires[key] = expiresAt
+	return nil
+}
+
+func (s *memoryStore) PurgeCache(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func newTestCache(next GmapsInterface, store CacheStore) GmapsInterface {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return NewCache(next, config.GMAPS_CACHE{GMAPS_TTL_SEARCH: 24 * time.Hour}, store, log)
+}
+
+func TestCacheSearchListPageToken(t *testing.T) {
+	tests := []struct {
+		name      string
+		nextToken string
+		wantTTL   time.Duration
+	}{
+		{name: "halaman dengan next_page_token", nextToken: "token-halaman-2", wantTTL: pageTokenTTL},
+		{name: "halaman terakhir", wantTTL: 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &countingGmaps{list: model.MapsList{Results: []model.Maps{{PlaceID: "a"}}, NextPageToken: tt.nextToken}}
+			store := &memoryStore{}
+			c := newTestCache(next, store)
+
+			for i := 0; i < 2; i++ {
+				res, err := c.GmapsSearchList(context.Background(), "pantai", model.SearchOptions{})
+				if err != nil {
+					t.Fatalf("GmapsSearchList: %v", err)
+				}
+				if res.NextPageToken != tt.nextToken {
+					t.Errorf("token = %q, mau %q", res.NextPageToken, tt.nextToken)
+				}
+			}
+			if next.calls["list"] != 1 {
+				t.Errorf("panggilan Google = %d, mau 1 (kedua dari cache)", next.calls["list"])
+			}
+
+			if len(store.expires) != 1 {
+				t.Fatalf("isi store = %d, mau 1", len(store.expires))
+			}
+			for _, exp := range store.expires {
+				if ttl := time.Until(exp); ttl > tt.wantTTL || ttl < tt.wantTTL-time.Minute {
+					t.Errorf("ttl cache = %v, mau %v", ttl, tt.wantTTL)
+				}
+			}
+		})
+	}
+}
+
+// Details yang menutup sesi autocomplete harus sampai ke Google walaupun ada di cache
+func TestCacheDetailsSessionToken(t *testing.T) {
+	next := &countingGmaps{}
+	c := newTestCache(next, nil)
+	ctx := context.Background()
+	session := utils.WithPlacesSession(ctx, "sesi-1")
+
+	steps := []struct {
+		name      string
+		ctx       context.Context
+		wantCalls int
+	}{
+		{"pertama kali", ctx, 1},
+		{"tanpa sesi dari cache", ctx, 1},
+		{"dengan sesi tetap ke Google", session, 2},
+		{"sesi berikutnya", utils.WithPlacesSession(ctx, "sesi-2"), 3},
+		{"tanpa sesi lagi dari cache", ctx, 3},
+	}
+	for _, s := range steps {
+		if _, err := c.GmapsSearchByPlaceID(s.ctx, "ChIJ-monas", "id"); err != nil {
+			t.Fatalf("%s: %v", s.name, err)
+		}
+		if next.calls["details"] != s.wantCalls {
+			t.Errorf("%s: panggilan details = %d, mau %d", s.name, next.calls["details"], s.wantCalls)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package gmaps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"proyek1/constant"
+	"proyek1/utils"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Client HTTP bersama untuk semua panggilan Google: timeout, retry dengan jitter untuk 5xx,
+// 429 dan OVER_QUERY_LIMIT, circuit breaker, dan API key tidak pernah masuk log/error
+type httpClient struct {
+	http       *http.Client
+	breaker    *breaker
+	maxRetries int
+	log        *logrus.Logger
+}
+
+type httpResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+func newHTTPClient(transport http.RoundTripper, timeout time.Duration, maxRetries int, log *logrus.Logger) *httpClient {
+	if timeout <= 0 {
+		timeout = constant.GmapsTimeout * time.Second
+	}
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+	if transport == nil {
+		transport = &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   5 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			TLSHandshakeTimeout:   5 * time.Second,
+			ResponseHeaderTimeout: timeout,
+			MaxIdleConnsPerHost:   20,
+			IdleConnTimeout:       90 * time.Second,
+		}
+	}
+	return &httpClient{
+		http:       &http.Client{Transport: transport, Timeout: timeout},
+		breaker:    newBreaker(constant.GmapsBreakerFailures, constant.GmapsBreakerCooldown*time.Second),
+		maxRetries: maxRetries,
+		log:        log,
+	}
+}
+
+func (c *httpClient) do(ctx context.Context, method, requestURL string, body []byte, header http.Header) (*httpResponse, error) {
+	if !c.breaker.allow() {
+		return nil, utils.ErrGmapsUnavailable
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			if err := sleepBackoff(ctx, attempt); err != nil {
+				c.breaker.release()
+				return nil, err
+			}
+		}
+
+		res, err := c.once(ctx, method, requestURL, body, header)
+		if err != nil {
+			// dibatalkan client (gin) bukan kesalahan Google, tidak diulang dan tidak dihitung breaker
+			if ctx.Err() != nil {
+				c.breaker.release()
+				return nil, ctx.Err()
+			}
+			lastErr = err
+			c.log.Warnf("Request gmaps %s %s gagal (percobaan %d): %v", method, redactKey(requestURL), attempt+1, err)
+			continue
+		}
+		if !retryable(res) {
+			c.breaker.success()
+			return res, nil
+		}
+		lastErr = fmt.Errorf("status %d", res.StatusCode)
+		c.log.Warnf("Request gmaps %s %s dibatasi/gagal (percobaan %d): status %d", method, redactKey(requestURL), attempt+1, res.StatusCode)
+		if attempt == c.maxRetries {
+			// response terakhir tetap dikembalikan supaya status dari Google bisa dibaca pemanggil
+			c.breaker.failure()
+			return res, nil
+		}
+	}
+
+	c.breaker.failure()
+	return nil, lastErr
+}
+
+func (c *httpClient) once(ctx context.Context, method, requestURL string, body []byte, header http.Header) (*httpResponse, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, reader)
+	if err != nil {
+		return nil, redactError(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, redactError(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, redactError(err)
+	}
+	return &httpResponse{StatusCode: resp.StatusCode, Header: resp.Header, Body: data}, nil
+}
+
+// 5xx, 429 dan OVER_QUERY_LIMIT (API lama mengembalikan 200 dengan status di body)
+func retryable(res *httpResponse) bool {
+	if res.StatusCode >= 500 || res.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if res.StatusCode != http.StatusOK || !bytes.Contains(res.Body, []byte("OVER_QUERY_LIMIT")) {
+		return false
+	}
+	var status struct {
+		Status string `json:"status"`
+	}
+	_ = json.Unmarshal(res.Body, &status)
+	return status.Status == "OVER_QUERY_LIMIT"
+}
+
+// Exponential backoff dengan full jitter: acak 0..min(cap, base*2^attempt)
+func sleepBackoff(ctx context.Context, attempt int) error {
+	max := constant.GmapsRetryBase * time.Millisecond << uint(attempt-1)
+	if limit := constant.GmapsRetryMax * time.Millisecond; max > limit {
+		max = limit
+	}
+	wait := time.Duration(rand.Int63n(int64(max) + 1))
+
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+var keyPattern = regexp.MustCompile(`([?&]key=)[^&\s"]*`)
+
+func redactKey(s string) string {
+	return keyPattern.ReplaceAllString(s, "${1}REDACTED")
+}
+
+// Error dari net/http menyertakan URL lengkap (termasuk key)
+func redactError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = redactKey(urlErr.URL)
+		return urlErr
+	}
+	return errors.New(redactKey(err.Error()))
+}
+
+// Circuit breaker sederhana: setelah `threshold` kegagalan berturut-turut, request langsung ditolak
+// selama `cooldown`, lalu satu request dicoba (half-open) sebelum ditutup lagi
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	return &breaker{threshold: threshold, cooldown: cooldown}
+}
+
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return true
+	}
+	if time.Now().Before(b.openUntil) || b.probing {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.probing = false
+}
+
+// Request probe dibatalkan sebelum ada hasil, slot probe dibuka lagi
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+func (b *breaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.probing = false
+	if b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.cooldown)
+	}
+}
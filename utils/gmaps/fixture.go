@@ -0,0 +1,178 @@
+package gmaps
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	ModeLive    = "live"
+	ModeFixture = "fixture"
+	ModeRecord  = "record"
+
+	DefaultFixtureDir = "./fixtures/gmaps"
+)
+
+// Satu response Google yang direkam. Body JSON disimpan apa adanya supaya mudah diedit,
+// selain JSON (foto) disimpan base64.
+type fixture struct {
+	Request    string          `json:"request"`
+	StatusCode int             `json:"status_code"`
+	Header     http.Header     `json:"header,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+	BodyBase64 []byte          `json:"body_base64,omitempty"`
+}
+
+// RoundTripper yang membaca response dari folder fixture, atau kalau record=true
+// meneruskan request ke next lalu menyimpan response-nya.
+type fixtureTransport struct {
+	dir    string
+	record bool
+	next   http.RoundTripper
+}
+
+func NewFixtureTransport(dir string, record bool, next http.RoundTripper) http.RoundTripper {
+	if dir == "" {
+		dir = DefaultFixtureDir
+	}
+	return &fixtureTransport{dir: dir, record: record, next: next}
+}
+
+func (t *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	endpoint, name, label := fixtureKey(req, body)
+	if t.record {
+		return t.recordResponse(req, name, label)
+	}
+
+	// Cari rekaman yang persis sama, kalau tidak ada pakai <endpoint>_default.json
+	for _, file := range []string{name, endpoint + "_default.json"} {
+		f, err := readFixture(filepath.Join(t.dir, file))
+		if err == nil {
+			return f.response(req), nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("fixture gmaps tidak ditemukan untuk %s (%s)", label, name)
+}
+
+func (t *fixtureTransport) recordResponse(req *http.Request, name, label string) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	f := fixture{
+		Request:    label,
+		StatusCode: resp.StatusCode,
+		Header:     http.Header{},
+	}
+	for _, h := range []string{"Content-Type", "Location"} {
+		if v := resp.Header.Get(h); v != "" {
+			f.Header.Set(h, v)
+		}
+	}
+	if json.Valid(data) {
+		f.Body = data
+	} else {
+		f.BodyBase64 = data
+	}
+
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return nil, err
+	}
+	out, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(t.dir, name), out, 0o644); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func readFixture(path string) (*fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("fixture %s rusak: %w", path, err)
+	}
+	return &f, nil
+}
+
+func (f *fixture) response(req *http.Request) *http.Response {
+	body := []byte(f.Body)
+	if len(f.BodyBase64) > 0 {
+		body = f.BodyBase64
+	}
+	header := f.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	status := f.StatusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return &http.Response{
+		StatusCode:    status,
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+var nonAlnum = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// Nama file fixture = endpoint + hash request. API key dan session token tidak ikut di-hash maupun disimpan.
+func fixtureKey(req *http.Request, body []byte) (endpoint, name, label string) {
+	query := req.URL.Query()
+	query.Del("key")
+	// session autocomplete acak per sesi, kalau ikut di-hash rekaman tidak pernah cocok
+	query.Del("sessiontoken")
+
+	endpoint = "content"
+	if strings.HasSuffix(req.URL.Host, "googleapis.com") {
+		path := strings.TrimPrefix(req.URL.Path, "/maps/api/")
+		path = strings.TrimSuffix(path, "/json")
+		endpoint = strings.Trim(nonAlnum.ReplaceAllString(path, "_"), "_")
+	}
+
+	label = fmt.Sprintf("%s %s%s?%s", req.Method, req.URL.Host, req.URL.Path, query.Encode())
+	sum := sha1.Sum(append([]byte(label+"\n"), body...))
+	name = fmt.Sprintf("%s_%s.json", endpoint, hex.EncodeToString(sum[:])[:12])
+	return endpoint, name, label
+}
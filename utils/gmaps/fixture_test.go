@@ -0,0 +1,160 @@
+package gmaps
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFixtureKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		endpoint string
+		label    string
+	}{
+		{
+			name:     "find place",
+			method:   http.MethodGet,
+			url:      "https://maps.googleapis.com/maps/api/place/findplacefromtext/json?input=monas&key=rahasia",
+			endpoint: "place_findplacefromtext",
+			label:    "GET maps.googleapis.com/maps/api/place/findplacefromtext/json?input=monas",
+		},
+		{
+			name:     "session token dibuang",
+			method:   http.MethodGet,
+			url:      "https://maps.googleapis.com/maps/api/place/autocomplete/json?input=mon&sessiontoken=abc&key=rahasia",
+			endpoint: "place_autocomplete",
+			label:    "GET maps.googleapis.com/maps/api/place/autocomplete/json?input=mon",
+		},
+		{
+			name:     "routes dengan body",
+			method:   http.MethodPost,
+			url:      "https://routes.googleapis.com/directions/v2:computeRoutes?key=rahasia",
+			body:     `{"travelMode":"DRIVE"}`,
+			endpoint: "directions_v2_computeRoutes",
+			label:    "POST routes.googleapis.com/directions/v2:computeRoutes?",
+		},
+		{
+			name:     "foto dari redirect",
+			method:   http.MethodGet,
+			url:      "https://lh3.googleusercontent.com/places/abc=s1600-w400",
+			endpoint: "content",
+			label:    "GET lh3.googleusercontent.com/places/abc=s1600-w400?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			endpoint, name, label := fixtureKey(req, []byte(tt.body))
+			if endpoint != tt.endpoint {
+				t.Errorf("endpoint = %q, mau %q", endpoint, tt.endpoint)
+			}
+			if label != tt.label {
+				t.Errorf("label = %q, mau %q", label, tt.label)
+			}
+			if !strings.HasPrefix(name, tt.endpoint+"_") || !strings.HasSuffix(name, ".json") || len(name) != len(tt.endpoint)+18 {
+				t.Errorf("nama file = %q", name)
+			}
+			if strings.Contains(label, "rahasia") {
+				t.Errorf("API key ikut tersimpan di label %q", label)
+			}
+		})
+	}
+}
+
+// Request yang cuma beda API key atau session token harus dapat rekaman yang sama,
+// beda query atau body harus beda rekaman
+func TestFixtureKeyCocok(t *testing.T) {
+	key := func(method, rawURL, body string) string {
+		req, err := http.NewRequest(method, rawURL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, name, _ := fixtureKey(req, []byte(body))
+		return name
+	}
+	const base = "https://maps.googleapis.com/maps/api/place/autocomplete/json"
+	const routes = "https://routes.googleapis.com/directions/v2:computeRoutes"
+
+	tests := []struct {
+		name string
+		a, b string
+		same bool
+	}{
+		{"beda api key", key("GET", base+"?input=mon&key=a", ""), key("GET", base+"?input=mon&key=b", ""), true},
+		{"beda session token", key("GET", base+"?input=mon&sessiontoken=1", ""), key("GET", base+"?input=mon&sessiontoken=2", ""), true},
+		{"urutan query", key("GET", base+"?input=mon&language=id", ""), key("GET", base+"?language=id&input=mon", ""), true},
+		{"beda input", key("GET", base+"?input=mon", ""), key("GET", base+"?input=mona", ""), false},
+		{"beda body", key("POST", routes, `{"travelMode":"DRIVE"}`), key("POST", routes, `{"travelMode":"WALK"}`), false},
+		{"beda method", key("GET", routes, ""), key("POST", routes, ""), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if (tt.a == tt.b) != tt.same {
+				t.Errorf("%q vs %q, sama = %v, mau %v", tt.a, tt.b, tt.a == tt.b, tt.same)
+			}
+		})
+	}
+}
+
+func TestFixtureTransportRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	const exactURL = "https://maps.googleapis.com/maps/api/geocode/json?address=monas&key=x"
+	req, _ := http.NewRequest(http.MethodGet, exactURL, nil)
+	_, exactName, _ := fixtureKey(req, nil)
+	writeFile(exactName, `{"status_code":200,"body":{"status":"exact"}}`)
+	writeFile("geocode_default.json", `{"status_code":200,"body":{"status":"default"}}`)
+	writeFile("place_details_default.json", `{"status_code":200,"body":`) // rusak
+
+	tests := []struct {
+		name    string
+		url     string
+		want    string
+		wantErr string
+	}{
+		{name: "rekaman persis", url: exactURL, want: `{"status":"exact"}`},
+		{name: "fallback default", url: "https://maps.googleapis.com/maps/api/geocode/json?address=kota+tua&key=x", want: `{"status":"default"}`},
+		{name: "tidak ada fixture", url: "https://maps.googleapis.com/maps/api/place/textsearch/json?query=pantai&key=x", wantErr: "fixture gmaps tidak ditemukan"},
+		{name: "fixture rusak", url: "https://maps.googleapis.com/maps/api/place/details/json?place_id=abc&key=x", wantErr: "rusak"},
+	}
+
+	transport := NewFixtureTransport(dir, false, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+			res, err := transport.RoundTrip(req)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, mau mengandung %q", err, tt.wantErr)
+				}
+				if strings.Contains(err.Error(), "key=x") {
+					t.Errorf("API key ikut di pesan error: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RoundTrip: %v", err)
+			}
+			defer res.Body.Close()
+			body, _ := io.ReadAll(res.Body)
+			if res.StatusCode != http.StatusOK || string(body) != tt.want {
+				t.Errorf("response = %d %s, mau 200 %s", res.StatusCode, body, tt.want)
+			}
+		})
+	}
+}
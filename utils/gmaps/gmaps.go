@@ -1,133 +1,223 @@
 package gmaps
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"io/ioutil"
-	"log"
 	"net/http"
 	"net/url"
 	"proyek1/config"
 	"proyek1/constant"
 	"proyek1/internal/model"
 	"proyek1/utils"
+	"proyek1/utils/geo"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
 )
 
 type GmapsInterface interface {
-	GmapsSearchObject(inputTempat string) (model.Maps, error)
-	GmapsSearchList(inputTempat string) ([]model.Maps, error)
-	GmapsSearchByPlaceID(placeID string) (model.MapsGetByPlaceId, error)
+	GmapsSearchObject(ctx context.Context, inputTempat string, opts model.SearchOptions) (model.Maps, error)
+	GmapsSearchList(ctx context.Context, inputTempat string, opts model.SearchOptions) (model.MapsList, error)
+	GmapsSearchByPlaceID(ctx context.Context, placeID, language string) (model.MapsGetByPlaceId, error)
 	PhotoReference(photoURl string) (string, error)
-	RouteToDestination(req model.RequestRouteMaps) (*model.ResponseRouteMaps, error)
+	RouteToDestination(ctx context.Context, req model.RequestRouteMaps) (*model.ResponseRouteMaps, error)
+	Geocode(ctx context.Context, address string) ([]model.GeocodeResult, error)
+	ReverseGeocode(ctx context.Context, lat, lng float64) ([]model.GeocodeResult, error)
+	GetPhoto(ctx context.Context, photoRef string, maxWidth, maxHeight int) (*model.PhotoFile, error)
+	Autocomplete(ctx context.Context, input string, opts model.SearchOptions) ([]model.Prediction, error)
 }
 
+const Source = "google"
+
 type gmapsStruct struct {
-	c config.GMAPS
+	c      config.GMAPS
+	client *httpClient
+	log    *logrus.Logger
 }
 
-func NewMail(c config.GMAPS) gmapsStruct {
+// GMAPS_MODE: live (default), fixture (baca response dari GMAPS_FIXTURE_DIR) atau record (panggil Google lalu simpan response)
+func NewMail(c config.GMAPS, log *logrus.Logger) gmapsStruct {
+	var transport http.RoundTripper
+	switch c.GMAPS_MODE {
+	case ModeFixture:
+		transport = NewFixtureTransport(c.GMAPS_FIXTURE_DIR, false, nil)
+	case ModeRecord:
+		transport = NewFixtureTransport(c.GMAPS_FIXTURE_DIR, true, http.DefaultTransport)
+	}
 	return gmapsStruct{
-		c: c,
+		c:      c,
+		client: newHTTPClient(transport, c.GMAPS_TIMEOUT, c.GMAPS_MAX_RETRIES, log),
+		log:    log,
 	}
 }
 
-func (c *gmapsStruct) GmapsSearchObject(inputTempat string) (model.Maps, error) {
-	encodedInput := url.QueryEscape(inputTempat)
-	requestURL := fmt.Sprintf("%s%s&inputtype=textquery&key=%s", constant.Gmaps, encodedInput, c.c.GMAPS_API_KEY)
-
-	resp, err := http.Get(requestURL)
+// GET lalu decode JSON, status HTTP selain 200 dianggap error
+func (c *gmapsStruct) getJSON(ctx context.Context, requestURL string, v interface{}) error {
+	res, err := c.client.do(ctx, http.MethodGet, requestURL, nil, nil)
 	if err != nil {
-		fmt.Println("Error melakukan permintaan:", err)
-		return model.Maps{}, err
+		return err
 	}
-	defer resp.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		c.log.Warnf("Gmaps %s status HTTP %d", redactKey(requestURL), res.StatusCode)
+		return httpStatusError(res.StatusCode)
+	}
+	if err := json.Unmarshal(res.Body, v); err != nil {
+		return fmt.Errorf("error unmarshal response: %w", err)
+	}
+	return nil
+}
 
-	// Baca response dari api
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Println("Error membaca body respons:", err)
-		return model.Maps{}, err
+// Find Place hanya kenal locationbias dan language, region tidak didukung dan type difilter dari hasil
+func (c *gmapsStruct) GmapsSearchObject(ctx context.Context, inputTempat string, opts model.SearchOptions) (model.Maps, error) {
+	params := url.Values{}
+	params.Set("inputtype", "textquery")
+	params.Set("language", searchLanguage(opts.Language))
+	if opts.Location != nil {
+		params.Set("locationbias", fmt.Sprintf("circle:%d@%f,%f", searchRadius(opts.Radius), opts.Location.Latitude, opts.Location.Longitude))
 	}
+	params.Set("key", c.c.GMAPS_API_KEY)
+	requestURL := constant.Gmaps + url.QueryEscape(inputTempat) + "&" + params.Encode()
 
 	var searchResponse model.GmapsAPIGetObject
-	if err := json.Unmarshal(body, &searchResponse); err != nil {
-		return model.Maps{}, fmt.Errorf("error unmarshal: %w", err)
+	if err := c.getJSON(ctx, requestURL, &searchResponse); err != nil {
+		return model.Maps{}, err
+	}
+	if err := placesStatusError(c.log, "findplacefromtext", searchResponse.Status, searchResponse.ErrorMessage); err != nil {
+		return model.Maps{}, err
 	}
 
-	var results model.Maps
+	// kandidat sudah urut relevansi dari Google, ambil yang pertama cocok dengan type
 	for _, v := range searchResponse.Candidates {
-		results = model.Maps{
+		if opts.Type != "" && !hasType(v.Types, opts.Type) {
+			continue
+		}
+		return model.Maps{
 			PlaceID: v.PlaceID,
 			Name:    v.Name,
 			Geometry: model.LocationResp{
 				Lat: fmt.Sprintf(`%f`, v.Geometry.Location.Lat),
 				Lng: fmt.Sprintf(`%f`, v.Geometry.Location.Lng),
 			},
-		}
+			Types:  v.Types,
+			Source: Source,
+		}, nil
 	}
 
-	return results, nil
+	return model.Maps{}, utils.ErrGmapsZeroResults
 }
 
-func (c *gmapsStruct) GmapsSearchList(inputTempat string) ([]model.Maps, error) {
-	encodedInput := url.QueryEscape(inputTempat)
-	requestURL := fmt.Sprintf("%s?query=%s&key=%s", constant.GmapsSearchText, encodedInput, c.c.GMAPS_API_KEY)
-
-	resp, err := http.Get(requestURL)
-	if err != nil {
-		fmt.Println("Error saat request:", err)
-		return nil, err
+// Kalau opts.PageToken ada, parameter lain diabaikan Google dan yang dikirim hanya pagetoken
+func (c *gmapsStruct) GmapsSearchList(ctx context.Context, inputTempat string, opts model.SearchOptions) (model.MapsList, error) {
+	if opts.PageToken != "" {
+		token, err := decodePageToken(opts.PageToken, inputTempat)
+		if err != nil {
+			return model.MapsList{}, err
+		}
+		return c.searchNextPage(ctx, inputTempat, token)
 	}
-	defer resp.Body.Close()
 
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Println("Error membaca response body:", err)
-		return nil, err
+	params := url.Values{}
+	params.Set("query", inputTempat)
+	params.Set("language", searchLanguage(opts.Language))
+	if opts.Location != nil {
+		params.Set("location", fmt.Sprintf("%f,%f", opts.Location.Latitude, opts.Location.Longitude))
+		params.Set("radius", strconv.Itoa(searchRadius(opts.Radius)))
+	}
+	if opts.Region != "" {
+		params.Set("region", opts.Region)
 	}
+	if opts.Type != "" {
+		params.Set("type", opts.Type)
+	}
+	params.Set("key", c.c.GMAPS_API_KEY)
+	requestURL := constant.GmapsSearchText + "?" + params.Encode()
 
 	var searchResponse model.GmapsAPIGetTextSearch
-	if err := json.Unmarshal(body, &searchResponse); err != nil {
-		return nil, fmt.Errorf("error unmarshal response: %w", err)
+	if err := c.getJSON(ctx, requestURL, &searchResponse); err != nil {
+		return model.MapsList{}, err
 	}
+	if err := placesStatusError(c.log, "textsearch", searchResponse.Status, searchResponse.ErrorMessage); err != nil {
+		return model.MapsList{}, err
+	}
+	return textSearchList(searchResponse, inputTempat), nil
+}
+
+// next_page_token baru valid beberapa detik setelah dibuat, sebelum itu Google menjawab INVALID_REQUEST.
+// Jadi INVALID_REQUEST dicoba lagi beberapa kali, kalau tetap gagal tokennya dianggap tidak valid/kedaluwarsa
+func (c *gmapsStruct) searchNextPage(ctx context.Context, inputTempat, token string) (model.MapsList, error) {
+	params := url.Values{}
+	params.Set("pagetoken", token)
+	params.Set("key", c.c.GMAPS_API_KEY)
+	requestURL := constant.GmapsSearchText + "?" + params.Encode()
+
+	for attempt := 1; ; attempt++ {
+		var searchResponse model.GmapsAPIGetTextSearch
+		if err := c.getJSON(ctx, requestURL, &searchResponse); err != nil {
+			return model.MapsList{}, err
+		}
+		if searchResponse.Status != "INVALID_REQUEST" {
+			if err := placesStatusError(c.log, "textsearch", searchResponse.Status, searchResponse.ErrorMessage); err != nil {
+				return model.MapsList{}, err
+			}
+			return textSearchList(searchResponse, inputTempat), nil
+		}
+		if attempt >= constant.SearchPageTokenTries {
+			return model.MapsList{}, utils.ErrPageToken
+		}
 
-	var results []model.Maps
+		t := time.NewTimer(constant.SearchPageTokenDelay * time.Second)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return model.MapsList{}, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+func textSearchList(searchResponse model.GmapsAPIGetTextSearch, inputTempat string) model.MapsList {
+	results := model.MapsList{
+		Results:       []model.Maps{},
+		NextPageToken: encodePageToken(searchResponse.NextPageToken, inputTempat),
+	}
 	for _, v := range searchResponse.Place {
-		results = append(results, model.Maps{
+		item := model.Maps{
 			PlaceID: v.PlaceID,
 			Name:    v.Name,
 			Geometry: model.LocationResp{
 				Lat: fmt.Sprintf(`%f`, v.Geometry.Location.Lat),
 				Lng: fmt.Sprintf(`%f`, v.Geometry.Location.Lng),
 			},
-		})
+			FormattedAddress: v.FormattedAddress,
+			Rating:           v.Rating,
+			Types:            v.Types,
+			Source:           Source,
+		}
+		if len(v.Photos) > 0 {
+			photo := v.Photos[0]
+			item.Photo = &photo
+		}
+		results.Results = append(results.Results, item)
 	}
-
-	return results, nil
+	return results
 }
 
-func (c *gmapsStruct) GmapsSearchByPlaceID(placeID string) (model.MapsGetByPlaceId, error) {
+func (c *gmapsStruct) GmapsSearchByPlaceID(ctx context.Context, placeID, language string) (model.MapsGetByPlaceId, error) {
 	encodedInput := url.QueryEscape(placeID)
-	requestURL := fmt.Sprintf("%s=%s&language=id&key=%s", constant.GmapsGetByPlaceID, encodedInput, c.c.GMAPS_API_KEY)
-
-	resp, err := http.Get(requestURL)
-	if err != nil {
-		fmt.Println("Error saat request:", err)
-		return model.MapsGetByPlaceId{}, err
+	requestURL := fmt.Sprintf("%s=%s&language=%s&key=%s", constant.GmapsGetByPlaceID, encodedInput, searchLanguage(language), c.c.GMAPS_API_KEY)
+	if session := utils.PlacesSessionFromContext(ctx); session != "" {
+		// menutup sesi autocomplete
+		requestURL += "&sessiontoken=" + url.QueryEscape(session)
 	}
-	defer resp.Body.Close()
 
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Println("Error membaca response body:", err)
+	var searchResponse model.GmapsAPIGetPlaceDetails
+	if err := c.getJSON(ctx, requestURL, &searchResponse); err != nil {
 		return model.MapsGetByPlaceId{}, err
 	}
-
-	var searchResponse model.GmapsAPIGetPlaceDetails
-	if err := json.Unmarshal(body, &searchResponse); err != nil {
-		return model.MapsGetByPlaceId{}, fmt.Errorf("error unmarshal response: %w", err)
+	if err := placesStatusError(c.log, "details", searchResponse.Status, searchResponse.ErrorMessage); err != nil {
+		return model.MapsGetByPlaceId{}, err
 	}
 
 	var parsedReviews []model.Review
@@ -180,65 +270,310 @@ func (c *gmapsStruct) GmapsSearchByPlaceID(placeID string) (model.MapsGetByPlace
 			searchResponse.Place.Geometry.Location.Lat,
 			searchResponse.Place.Geometry.Location.Lng,
 			placeID),
-		Photos:         photos,
-		BusinessStatus: searchResponse.Place.BusinessStatus,
-		Types:          searchResponse.Place.Types,
+		Photos:            photos,
+		BusinessStatus:    searchResponse.Place.BusinessStatus,
+		Types:             searchResponse.Place.Types,
+		AddressComponents: searchResponse.Place.AddressComponents,
+		Source:            Source,
+	}
+
+	return results, nil
+}
+
+// Prediksi tempat per ketikan. Session token dari context, lokasi user jadi bias dan origin (untuk distance_meters)
+func (c *gmapsStruct) Autocomplete(ctx context.Context, input string, opts model.SearchOptions) ([]model.Prediction, error) {
+	params := url.Values{}
+	params.Set("input", input)
+	params.Set("language", searchLanguage(opts.Language))
+	if session := utils.PlacesSessionFromContext(ctx); session != "" {
+		params.Set("sessiontoken", session)
+	}
+	if opts.Location != nil {
+		latlng := fmt.Sprintf("%f,%f", opts.Location.Latitude, opts.Location.Longitude)
+		params.Set("location", latlng)
+		params.Set("origin", latlng)
+		params.Set("radius", strconv.Itoa(searchRadius(opts.Radius)))
+	}
+	if opts.Region != "" {
+		params.Set("region", opts.Region)
+	}
+	if opts.Type != "" {
+		params.Set("types", opts.Type)
+	}
+	params.Set("key", c.c.GMAPS_API_KEY)
+	requestURL := constant.GmapsAutocomplete + "?" + params.Encode()
+
+	var autocompleteResponse model.GmapsAPIAutocomplete
+	if err := c.getJSON(ctx, requestURL, &autocompleteResponse); err != nil {
+		return nil, err
+	}
+	// tidak ada prediksi bukan error untuk autocomplete
+	if autocompleteResponse.Status == "ZERO_RESULTS" {
+		return []model.Prediction{}, nil
+	}
+	if err := placesStatusError(c.log, "autocomplete", autocompleteResponse.Status, autocompleteResponse.ErrorMessage); err != nil {
+		return nil, err
+	}
+
+	results := []model.Prediction{}
+	for _, v := range autocompleteResponse.Predictions {
+		results = append(results, model.Prediction{
+			PlaceID:        v.PlaceID,
+			Description:    v.Description,
+			MainText:       v.StructuredFormatting.MainText,
+			SecondaryText:  v.StructuredFormatting.SecondaryText,
+			Types:          v.Types,
+			DistanceMeters: v.DistanceMeters,
+			Source:         Source,
+		})
 	}
+	return results, nil
+}
+
+func (c *gmapsStruct) Geocode(ctx context.Context, address string) ([]model.GeocodeResult, error) {
+	requestURL := fmt.Sprintf("%s?address=%s&language=id&key=%s", constant.GmapsGeocode, url.QueryEscape(address), c.c.GMAPS_API_KEY)
+	return c.geocode(ctx, requestURL)
+}
 
+func (c *gmapsStruct) ReverseGeocode(ctx context.Context, lat, lng float64) ([]model.GeocodeResult, error) {
+	requestURL := fmt.Sprintf("%s?latlng=%f,%f&language=id&key=%s", constant.GmapsGeocode, lat, lng, c.c.GMAPS_API_KEY)
+	return c.geocode(ctx, requestURL)
+}
+
+func (c *gmapsStruct) geocode(ctx context.Context, requestURL string) ([]model.GeocodeResult, error) {
+	var geocodeResponse model.GmapsAPIGeocode
+	if err := c.getJSON(ctx, requestURL, &geocodeResponse); err != nil {
+		return nil, err
+	}
+	if err := placesStatusError(c.log, "geocode", geocodeResponse.Status, geocodeResponse.ErrorMessage); err != nil {
+		return nil, err
+	}
+
+	var results []model.GeocodeResult
+	for _, v := range geocodeResponse.Results {
+		results = append(results, model.GeocodeResult{
+			PlaceID:          v.PlaceID,
+			FormattedAddress: v.FormattedAddress,
+			Geometry: model.LocationResp{
+				Lat: fmt.Sprintf("%f", v.Geometry.Location.Lat),
+				Lng: fmt.Sprintf("%f", v.Geometry.Location.Lng),
+			},
+			Types:             v.Types,
+			AddressComponents: v.AddressComponents,
+			Source:            Source,
+		})
+	}
 	return results, nil
 }
 
 func (c *gmapsStruct) PhotoReference(photoURl string) (string, error) {
-	if photoURl == "" {
+	return c.photoURL(photoURl, constant.PhotoDefaultWidth, 0)
+}
+
+// Google butuh minimal salah satu dari maxwidth/maxheight
+func (c *gmapsStruct) photoURL(photoRef string, maxWidth, maxHeight int) (string, error) {
+	if photoRef == "" {
 		return "", fmt.Errorf("empty photo reference")
 	}
-	photoURL := fmt.Sprintf(
-		"https://maps.googleapis.com/maps/api/place/photo?maxwidth=400&photo_reference=%s&key=%s",
-		photoURl,
-		c.c.GMAPS_API_KEY,
-	)
+	if maxWidth <= 0 && maxHeight <= 0 {
+		maxWidth = constant.PhotoDefaultWidth
+	}
+	params := url.Values{}
+	if maxWidth > 0 {
+		params.Set("maxwidth", strconv.Itoa(maxWidth))
+	}
+	if maxHeight > 0 {
+		params.Set("maxheight", strconv.Itoa(maxHeight))
+	}
+	params.Set("photo_reference", photoRef)
+	params.Set("key", c.c.GMAPS_API_KEY)
 
-	return photoURL, nil
+	return constant.GmapsPhoto + "?" + params.Encode(), nil
 }
 
-func (c *gmapsStruct) RouteToDestination(req model.RequestRouteMaps) (*model.ResponseRouteMaps, error) {
-	var client = &http.Client{}
+func (c *gmapsStruct) GetPhoto(ctx context.Context, photoRef string, maxWidth, maxHeight int) (*model.PhotoFile, error) {
+	photoURL, err := c.photoURL(photoRef, maxWidth, maxHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.client.do(ctx, http.MethodGet, photoURL, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		c.log.Warnf("Gagal mengambil foto, status HTTP %d", res.StatusCode)
+		return nil, httpStatusError(res.StatusCode)
+	}
+	return &model.PhotoFile{
+		ContentType: res.Header.Get("Content-Type"),
+		Data:        res.Body,
+	}, nil
+}
 
+func (c *gmapsStruct) RouteToDestination(ctx context.Context, req model.RequestRouteMaps) (*model.ResponseRouteMaps, error) {
 	requestURL := fmt.Sprintf("%s?key=%s", constant.GmapsGetRouteByPlaceID, c.c.GMAPS_API_KEY)
-	fmt.Println(requestURL)
 	jsonData, err := json.Marshal(req)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	request, err := http.NewRequest("POST", requestURL, bytes.NewReader(jsonData))
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("X-Goog-FieldMask", constant.GmapsRouteFieldMask)
+
+	response, err := c.client.do(ctx, http.MethodPost, requestURL, jsonData, header)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, routesStatusError(c.log, response)
 	}
 
-	// fieldMask := "routes.duration,routes.distanceMeters,routes.polyline.encodedPolyline"
-	request.Header.Set("Content-Type", "application/json")
-	request.Header.Set("X-Goog-FieldMask", "*")
+	res := &model.ResponseRouteMaps{}
+	if err := json.Unmarshal(response.Body, &res); err != nil {
+		return nil, fmt.Errorf("error unmarshal response: %w", err)
+	}
+	// Routes API mengembalikan {} kalau tidak ada rute
+	if len(res.Routes) == 0 {
+		return nil, utils.ErrGmapsZeroResults
+	}
 
-	response, err := client.Do(request)
-	if err != nil {
+	if err := ParseRoutes(res); err != nil {
 		return nil, err
 	}
+	return res, nil
+}
 
-	fmt.Println("ini response.body:", response.Body)
-	bodyBytes, err := io.ReadAll(response.Body)
-	if err != nil {
-		log.Fatal(err)
+// Decode polyline dan isi teks jarak/durasi bahasa Indonesia, dipakai juga oleh provider routing lain
+func ParseRoutes(res *model.ResponseRouteMaps) error {
+	for i := range res.Routes {
+		route := &res.Routes[i]
+		route.DistanceText = utils.FormatJarak(route.Distance)
+		route.DurationText = utils.FormatDurasi(route.Duration)
+		coords, err := decodeCoordinates(route.Polyline.EncodePolyline)
+		if err != nil {
+			return err
+		}
+		route.Coordinates = coords
+
+		for j := range route.Legs {
+			leg := &route.Legs[j]
+			leg.DistanceText = utils.FormatJarak(leg.Distance)
+			leg.DurationText = utils.FormatDurasi(leg.Duration)
+
+			for k := range leg.Steps {
+				step := &leg.Steps[k]
+				step.DistanceText = utils.FormatJarak(step.Distance)
+				step.DurationText = utils.FormatDurasi(step.Duration)
+				coords, err := decodeCoordinates(step.Polyline.EncodePolyline)
+				if err != nil {
+					return err
+				}
+				step.Coordinates = coords
+			}
+		}
+		route.TransitSteps = parseTransitSteps(route.Legs)
 	}
-	bodyString := string(bodyBytes)
-	fmt.Println("Full Response Body:\n", bodyString) // Cetak seluruh body
+	return nil
+}
 
-	res := &model.ResponseRouteMaps{}
-	err = json.Unmarshal(bodyBytes, &res)
+// Gabungkan step jalan kaki yang berurutan, step kendaraan umum jadi satu segmen per jalur
+func parseTransitSteps(legs []model.RouteLeg) []model.TransitSegment {
+	var segments []model.TransitSegment
+	hasTransit := false
+	var walkSeconds time.Duration
+	for _, leg := range legs {
+		for _, step := range leg.Steps {
+			if step.TransitDetails == nil {
+				stepDuration, _ := time.ParseDuration(step.Duration)
+				if n := len(segments); n > 0 && segments[n-1].Type == "WALK" {
+					segments[n-1].Distance += step.Distance
+					walkSeconds += stepDuration
+					segments[n-1].Duration = fmt.Sprintf("%ds", int(walkSeconds.Seconds()))
+					continue
+				}
+				walkSeconds = stepDuration
+				segments = append(segments, model.TransitSegment{
+					Type:     "WALK",
+					Distance: step.Distance,
+					Duration: step.Duration,
+				})
+				continue
+			}
+
+			hasTransit = true
+			t := step.TransitDetails
+			segment := model.TransitSegment{
+				Type:          "TRANSIT",
+				Distance:      step.Distance,
+				Duration:      step.Duration,
+				LineName:      t.TransitLine.Name,
+				LineShortName: t.TransitLine.NameShort,
+				LineColor:     t.TransitLine.Color,
+				VehicleType:   t.TransitLine.Vehicle.Type,
+				VehicleName:   t.TransitLine.Vehicle.Name.Text,
+				Headsign:      t.Headsign,
+				DepartureStop: t.StopDetails.DepartureStop.Name,
+				ArrivalStop:   t.StopDetails.ArrivalStop.Name,
+				DepartureTime: t.LocalizedValues.DepartureTime.Time.Text,
+				ArrivalTime:   t.LocalizedValues.ArrivalTime.Time.Text,
+				StopCount:     t.StopCount,
+			}
+			if segment.DepartureTime == "" {
+				segment.DepartureTime = t.StopDetails.DepartureTime
+			}
+			if segment.ArrivalTime == "" {
+				segment.ArrivalTime = t.StopDetails.ArrivalTime
+			}
+			if len(t.TransitLine.Agencies) > 0 {
+				segment.Agency = t.TransitLine.Agencies[0].Name
+			}
+			segments = append(segments, segment)
+		}
+	}
+	if !hasTransit {
+		return nil
+	}
+
+	for i := range segments {
+		segments[i].DistanceText = utils.FormatJarak(segments[i].Distance)
+		segments[i].DurationText = utils.FormatDurasi(segments[i].Duration)
+	}
+	return segments
+}
+
+func decodeCoordinates(encoded string) ([]model.LatLng, error) {
+	points, err := geo.DecodePolyline(encoded)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("error decode polyline: %w", err)
+	}
+	coords := make([]model.LatLng, 0, len(points))
+	for _, p := range points {
+		coords = append(coords, model.LatLng{Latitude: p.Lat, Longitude: p.Lng})
 	}
-	fmt.Println("ini res:", res)
+	return coords, nil
+}
 
-	return res, nil
+func searchLanguage(language string) string {
+	if language == "" {
+		return constant.SearchDefaultLanguage
+	}
+	return language
+}
+
+func searchRadius(radius int) int {
+	if radius <= 0 {
+		return constant.SearchDefaultRadius
+	}
+	return radius
+}
+
+func hasType(types []string, t string) bool {
+	for _, v := range types {
+		if v == t {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,163 @@
+package gmaps
+
+import (
+	"context"
+	"errors"
+	"io"
+	"proyek1/config"
+	"proyek1/internal/model"
+	"proyek1/utils"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+const testFixtureDir = "../../fixtures/gmaps"
+
+func newFixtureClient(t *testing.T) gmapsStruct {
+	t.Helper()
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return NewMail(config.GMAPS{
+		GMAPS_API_KEY:     "test-key",
+		GMAPS_MODE:        ModeFixture,
+		GMAPS_FIXTURE_DIR: testFixtureDir,
+	}, log)
+}
+
+// Monas ke Blok M Square naik TransJakarta koridor 1 lalu MRT, rekaman ada di fixtures/gmaps
+func transitRouteRequest() model.RequestRouteMaps {
+	return model.RequestRouteMaps{
+		Origin:        model.Waypoint{Location: model.LocationReq{LatLng: model.LatLng{Latitude: -6.17539, Longitude: 106.82715}}},
+		Destination:   model.Waypoint{Location: model.LocationReq{LatLng: model.LatLng{Latitude: -6.2446, Longitude: 106.8003}}},
+		TravelMode:    "TRANSIT",
+		DepartureTime: "2025-01-06T08:00:00+07:00",
+		LanguageCode:  "id",
+	}
+}
+
+func TestRouteToDestinationTransit(t *testing.T) {
+	c := newFixtureClient(t)
+	res, err := c.RouteToDestination(context.Background(), transitRouteRequest())
+	if err != nil {
+		t.Fatalf("RouteToDestination: %v", err)
+	}
+	if len(res.Routes) != 1 {
+		t.Fatalf("jumlah rute = %d, mau 1", len(res.Routes))
+	}
+	route := res.Routes[0]
+	if route.DistanceText != "9,2 km" || route.DurationText != "42 menit" {
+		t.Errorf("ringkasan rute = %q / %q", route.DistanceText, route.DurationText)
+	}
+	if len(route.Coordinates) != 7 {
+		t.Errorf("jumlah koordinat rute = %d, mau 7", len(route.Coordinates))
+	}
+
+	want := []model.TransitSegment{
+		// dua step jalan kaki pertama digabung
+		{Type: "WALK", Distance: 470, Duration: "370s", DistanceText: "470 m", DurationText: "6 menit"},
+		{
+			Type: "TRANSIT", Distance: 1950, Duration: "540s", DistanceText: "1,9 km", DurationText: "9 menit",
+			LineName: "Blok M - Kota", LineShortName: "1", LineColor: "#d7282f",
+			VehicleType: "BUS", VehicleName: "Bus", Agency: "TransJakarta", Headsign: "Blok M",
+			DepartureStop: "Monumen Nasional", ArrivalStop: "Bundaran HI",
+			DepartureTime: "08.10", ArrivalTime: "08.19", StopCount: 3,
+		},
+		{Type: "WALK", Distance: 40, Duration: "120s", DistanceText: "40 m", DurationText: "2 menit"},
+		{
+			Type: "TRANSIT", Distance: 6500, Duration: "780s", DistanceText: "6,5 km", DurationText: "13 menit",
+			LineName: "MRT Lin Utara-Selatan", LineShortName: "M", LineColor: "#0054a6",
+			VehicleType: "SUBWAY", VehicleName: "Kereta bawah tanah", Agency: "MRT Jakarta", Headsign: "Lebak Bulus Grab",
+			DepartureStop: "Bundaran HI Bank DKI", ArrivalStop: "Blok M BCA",
+			DepartureTime: "08.25", ArrivalTime: "08.38", StopCount: 6,
+		},
+		{Type: "WALK", Distance: 260, Duration: "240s", DistanceText: "260 m", DurationText: "4 menit"},
+	}
+	if len(route.TransitSteps) != len(want) {
+		t.Fatalf("jumlah segmen = %d, mau %d: %+v", len(route.TransitSteps), len(want), route.TransitSteps)
+	}
+	for i, w := range want {
+		if got := route.TransitSteps[i]; got != w {
+			t.Errorf("segmen %d:\n got %+v\nwant %+v", i, got, w)
+		}
+	}
+}
+
+func TestRouteToDestinationDriveTanpaTransit(t *testing.T) {
+	c := newFixtureClient(t)
+	req := transitRouteRequest()
+	req.TravelMode = "DRIVE"
+	res, err := c.RouteToDestination(context.Background(), req)
+	if err != nil {
+		t.Fatalf("RouteToDestination: %v", err)
+	}
+	route := res.Routes[0]
+	if route.TransitSteps != nil {
+		t.Errorf("rute DRIVE tidak boleh punya transitSteps, dapat %+v", route.TransitSteps)
+	}
+	if len(route.Legs) != 1 || len(route.Legs[0].Steps) != 3 {
+		t.Fatalf("legs/steps tidak sesuai fixture default")
+	}
+	if got := route.Legs[0].Steps[1].NavigationInstruction.Instructions; got != "Belok kiri ke Jl. Gajah Mada" {
+		t.Errorf("instruksi step 2 = %q", got)
+	}
+}
+
+// Jam berangkat/tiba pakai stopDetails kalau localizedValues kosong
+func TestParseTransitStepsFallbackWaktu(t *testing.T) {
+	details := &model.TransitDetails{
+		StopDetails: model.TransitStopDetails{
+			DepartureStop: model.TransitStop{Name: "Dukuh Atas BNI"},
+			DepartureTime: "2025-01-06T01:00:00Z",
+			ArrivalStop:   model.TransitStop{Name: "Lebak Bulus Grab"},
+			ArrivalTime:   "2025-01-06T01:30:00Z",
+		},
+	}
+	details.TransitLine.Vehicle.Type = "SUBWAY"
+	legs := []model.RouteLeg{{Steps: []model.RouteStep{
+		{Distance: 15000, Duration: "1800s", TransitDetails: details},
+	}}}
+
+	segments := parseTransitSteps(legs)
+	if len(segments) != 1 {
+		t.Fatalf("jumlah segmen = %d, mau 1", len(segments))
+	}
+	if segments[0].DepartureTime != "2025-01-06T01:00:00Z" || segments[0].ArrivalTime != "2025-01-06T01:30:00Z" {
+		t.Errorf("waktu = %q - %q", segments[0].DepartureTime, segments[0].ArrivalTime)
+	}
+	if segments[0].DepartureStop != "Dukuh Atas BNI" || segments[0].ArrivalStop != "Lebak Bulus Grab" {
+		t.Errorf("halte = %q - %q", segments[0].DepartureStop, segments[0].ArrivalStop)
+	}
+}
+
+// Rekaman "pantai ancol" berisi hotel dulu, baru dua tempat wisata
+func TestGmapsSearchObjectType(t *testing.T) {
+	c := newFixtureClient(t)
+	tests := []struct {
+		name    string
+		typ     string
+		want    string
+		wantErr error
+	}{
+		{name: "tanpa filter ambil kandidat teratas", want: "Putri Duyung Resort Ancol"},
+		{name: "kandidat pertama yang cocok, bukan yang terakhir", typ: "tourist_attraction", want: "Pantai Ancol"},
+		{name: "tidak ada yang cocok", typ: "museum", wantErr: utils.ErrGmapsZeroResults},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := c.GmapsSearchObject(context.Background(), "pantai ancol", model.SearchOptions{Type: tt.typ})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, mau %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GmapsSearchObject: %v", err)
+			}
+			if res.Name != tt.want {
+				t.Errorf("nama = %q, mau %q", res.Name, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package gmaps
+
+import (
+	"context"
+	"proyek1/config"
+	"proyek1/constant"
+	"proyek1/internal/model"
+	"proyek1/utils"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Jenis panggilan ke Google, dipakai untuk metering, budget, harga dan jenis cache
+const (
+	EndpointSearch       = "search"
+	EndpointList         = "list"
+	EndpointDetails      = "details"
+	EndpointPhoto        = "photo"
+	EndpointRoute        = "route"
+	EndpointGeocode      = "geocode"
+	EndpointReverse      = "reverse"
+	EndpointAutocomplete = "autocomplete"
+
+	meterFlushEvery = 30 * time.Second
+	meterDayFormat  = "2006-01-02"
+)
+
+var Endpoints = []string{EndpointSearch, EndpointList, EndpointDetails, EndpointPhoto, EndpointRoute, EndpointGeocode, EndpointReverse, EndpointAutocomplete}
+
+// Penyimpanan jumlah panggilan per hari, endpoint dan user (tabel gmaps_usage)
+type UsageStore interface {
+	AddUsage(ctx context.Context, day time.Time, endpoint, userID string, calls int) error
+	GetUsageTotals(ctx context.Context, from, to time.Time) (map[string]int, error)
+}
+
+// GmapsInterface yang dihitung, Close dipanggil saat server berhenti supaya hitungan terakhir tersimpan
+type Meter interface {
+	GmapsInterface
+	Close()
+}
+
+type usageKey struct {
+	day      string
+	endpoint string
+	userID   string
+}
+
+type meteredGmaps struct {
+	next  GmapsInterface
+	store UsageStore
+	quota config.GMAPS_QUOTA
+	log   *logrus.Logger
+
+	mu      sync.Mutex
+	day     string
+	month   string
+	daily   map[string]int
+	monthly map[string]int
+	pending map[usageKey]int
+	warned  map[string]bool
+
+	ticker    *time.Ticker
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Bungkus client Google paling dalam, jadi yang dihitung hanya panggilan yang benar-benar ke Google
+// (cache hit tidak dihitung). Jumlah dikumpulkan di memory lalu ditulis ke store tiap 30 detik
+func NewMeter(next GmapsInterface, c config.GMAPS_QUOTA, store UsageStore, log *logrus.Logger) Meter {
+	if c.GMAPS_BUDGET_WARN <= 0 {
+		c.GMAPS_BUDGET_WARN = constant.GmapsBudgetWarn
+	}
+	m := &meteredGmaps{
+		next:    next,
+		store:   store,
+		quota:   c,
+		log:     log,
+		daily:   make(map[string]int),
+		monthly: make(map[string]int),
+		pending: make(map[usageKey]int),
+		warned:  make(map[string]bool),
+		ticker:  time.NewTicker(meterFlushEvery),
+		done:    make(chan struct{}),
+	}
+	m.roll(time.Now())
+	m.reload()
+
+	go func() {
+		for {
+			select {
+			case <-m.ticker.C:
+				m.flush()
+			case <-m.done:
+				return
+			}
+		}
+	}()
+	return m
+}
+
+// Hentikan flush berkala lalu tulis sisa hitungan sekali lagi
+func (m *meteredGmaps) Close() {
+	m.closeOnce.Do(func() {
+		m.ticker.Stop()
+		close(m.done)
+		m.flush()
+	})
+}
+
+func (m *meteredGmaps) GmapsSearchObject(ctx context.Context, inputTempat string, opts model.SearchOptions) (model.Maps, error) {
+	if err := m.use(ctx, EndpointSearch); err != nil {
+		return model.Maps{}, err
+	}
+	return m.next.GmapsSearchObject(ctx, inputTempat, opts)
+}
+
+func (m *meteredGmaps) GmapsSearchList(ctx context.Context, inputTempat string, opts model.SearchOptions) (model.MapsList, error) {
+	if err := m.use(ctx, EndpointList); err != nil {
+		return model.MapsList{}, err
+	}
+	return m.next.GmapsSearchList(ctx, inputTempat, opts)
+}
+
+func (m *meteredGmaps) GmapsSearchByPlaceID(ctx context.Context, placeID, language string) (model.MapsGetByPlaceId, error) {
+	if err := m.use(ctx, EndpointDetails); err != nil {
+		return model.MapsGetByPlaceId{}, err
+	}
+	return m.next.GmapsSearchByPlaceID(ctx, placeID, language)
+}
+
+func (m *meteredGmaps) PhotoReference(photoURl string) (string, error) {
+	return m.next.PhotoReference(photoURl)
+}
+
+func (m *meteredGmaps) RouteToDestination(ctx context.Context, req model.RequestRouteMaps) (*model.ResponseRouteMaps, error) {
+	if err := m.use(ctx, EndpointRoute); err != nil {
+		return nil, err
+	}
+	return m.next.RouteToDestination(ctx, req)
+}
+
+func (m *meteredGmaps) Geocode(ctx context.Context, address string) ([]model.GeocodeResult, error) {
+	if err := m.use(ctx, EndpointGeocode); err != nil {
+		return nil, err
+	}
+	return m.next.Geocode(ctx, address)
+}
+
+func (m *meteredGmaps) ReverseGeocode(ctx context.Context, lat, lng float64) ([]model.GeocodeResult, error) {
+	if err := m.use(ctx, EndpointReverse); err != nil {
+		return nil, err
+	}
+	return m.next.ReverseGeocode(ctx, lat, lng)
+}
+
+func (m *meteredGmaps) Autocomplete(ctx context.Context, input string, opts model.SearchOptions) ([]model.Prediction, error) {
+	if err := m.use(ctx, EndpointAutocomplete); err != nil {
+		return nil, err
+	}
+	return m.next.Autocomplete(ctx, input, opts)
+}
+
+func (m *meteredGmaps) GetPhoto(ctx context.Context, photoRef string, maxWidth, maxHeight int) (*model.PhotoFile, error) {
+	if err := m.use(ctx, EndpointPhoto); err != nil {
+		return nil, err
+	}
+	return m.next.GetPhoto(ctx, photoRef, maxWidth, maxHeight)
+}
+
+// Cek budget lalu catat satu panggilan. Panggilan yang gagal di Google tetap dihitung
+// karena tetap ditagih
+func (m *meteredGmaps) use(ctx context.Context, endpoint string) error {
+	var userID string
+	if user, ok := utils.UserFromContext(ctx); ok {
+		userID = user.ID
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.roll(time.Now())
+
+	daily, monthly := m.quota.GMAPS_BUDGET_DAILY[endpoint], m.quota.GMAPS_BUDGET_MONTHLY[endpoint]
+	if (daily > 0 && m.daily[endpoint] >= daily) || (monthly > 0 && m.monthly[endpoint] >= monthly) {
+		return utils.ErrQuotaExceeded
+	}
+
+	m.daily[endpoint]++
+	m.monthly[endpoint]++
+	m.pending[usageKey{day: m.day, endpoint: endpoint, userID: userID}]++
+	m.warn(endpoint, "harian", m.daily[endpoint], daily)
+	m.warn(endpoint, "bulanan", m.monthly[endpoint], monthly)
+	return nil
+}
+
+// Dipanggil dengan mu terkunci, cukup sekali per hari per endpoint
+func (m *meteredGmaps) warn(endpoint, period string, used, budget int) {
+	if budget <= 0 || used*100 < budget*m.quota.GMAPS_BUDGET_WARN {
+		return
+	}
+	key := period + ":" + endpoint
+	if m.warned[key] {
+		return
+	}
+	m.warned[key] = true
+	m.log.Warnf("Penggunaan gmaps %s sudah %d dari budget %s %d", endpoint, used, period, budget)
+}
+
+// Reset hitungan saat ganti hari/bulan. Dipanggil dengan mu terkunci
+func (m *meteredGmaps) roll(now time.Time) {
+	day := now.Format(meterDayFormat)
+	if day == m.day {
+		return
+	}
+	month := now.Format("2006-01")
+	if month != m.month {
+		m.monthly = make(map[string]int)
+		m.month = month
+	}
+	m.day = day
+	m.daily = make(map[string]int)
+	m.warned = make(map[string]bool)
+}
+
+func (m *meteredGmaps) flush() {
+	if m.store == nil {
+		return
+	}
+	m.mu.Lock()
+	pending := m.pending
+	m.pending = make(map[usageKey]int)
+	m.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var failed bool
+	for k, calls := range pending {
+		day, _ := time.Parse(meterDayFormat, k.day)
+		if err := m.store.AddUsage(ctx, day, k.endpoint, k.userID, calls); err != nil {
+			failed = true
+			m.mu.Lock()
+			m.pending[k] += calls
+			m.mu.Unlock()
+		}
+	}
+	if failed {
+		m.log.Warn("Gagal menyimpan sebagian data penggunaan gmaps, dicoba lagi nanti")
+		return
+	}
+	m.reload()
+}
+
+// Ambil total dari store (termasuk instance lain), ditambah yang belum ditulis
+func (m *meteredGmaps) reload() {
+	if m.store == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	today, _ := time.Parse(meterDayFormat, now.Format(meterDayFormat))
+	monthStart := today.AddDate(0, 0, 1-today.Day())
+
+	daily, err := m.store.GetUsageTotals(ctx, today, today)
+	if err != nil {
+		m.log.Warnf("Gagal membaca penggunaan gmaps: %v", err)
+		return
+	}
+	monthly, err := m.store.GetUsageTotals(ctx, monthStart, today)
+	if err != nil {
+		m.log.Warnf("Gagal membaca penggunaan gmaps: %v", err)
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.roll(now)
+	if m.day != today.Format(meterDayFormat) {
+		return
+	}
+	for k, calls := range m.pending {
+		if k.day == m.day {
+			daily[k.endpoint] += calls
+			monthly[k.endpoint] += calls
+		}
+	}
+	m.daily = daily
+	m.monthly = monthly
+}
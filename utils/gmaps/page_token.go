@@ -0,0 +1,34 @@
+package gmaps
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"proyek1/utils"
+)
+
+// Token halaman yang dikirim ke client: next_page_token Google + query asal,
+// supaya token tidak bisa dipakai untuk pencarian lain
+type pageToken struct {
+	Token string `json:"t"`
+	Query string `json:"q"`
+}
+
+func encodePageToken(googleToken, query string) string {
+	if googleToken == "" {
+		return ""
+	}
+	data, _ := json.Marshal(pageToken{Token: googleToken, Query: normalizeQuery(query)})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePageToken(token, query string) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", utils.ErrPageToken
+	}
+	var t pageToken
+	if err := json.Unmarshal(data, &t); err != nil || t.Token == "" || t.Query != normalizeQuery(query) {
+		return "", utils.ErrPageToken
+	}
+	return t.Token, nil
+}
@@ -0,0 +1,75 @@
+package gmaps
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"proyek1/config"
+	"proyek1/constant"
+	"proyek1/internal/model"
+	"proyek1/utils/cache"
+
+	"github.com/sirupsen/logrus"
+)
+
+type photoCacheGmaps struct {
+	GmapsInterface
+	disk  *cache.DiskLRU
+	group cache.Group
+	log   *logrus.Logger
+}
+
+// Bungkus GetPhoto dengan cache di disk (LRU, dibatasi PHOTO_CACHE_MAX_MB).
+// Kalau folder cache tidak bisa dipakai, foto tetap diambil langsung dari Google
+func NewPhotoCache(next GmapsInterface, c config.PHOTO, log *logrus.Logger) GmapsInterface {
+	dir := c.PHOTO_CACHE_DIR
+	if dir == "" {
+		dir = constant.PhotoCacheDir
+	}
+	maxMB := c.PHOTO_CACHE_MAX_MB
+	if maxMB <= 0 {
+		maxMB = constant.PhotoCacheMaxMB
+	}
+
+	disk, err := cache.NewDiskLRU(dir, int64(maxMB)<<20)
+	if err != nil {
+		log.Warnf("Cache foto dimatikan, folder %s tidak bisa dipakai: %v", dir, err)
+		return next
+	}
+	return &photoCacheGmaps{
+		GmapsInterface: next,
+		disk:           disk,
+		log:            log,
+	}
+}
+
+func (p *photoCacheGmaps) GetPhoto(ctx context.Context, photoRef string, maxWidth, maxHeight int) (*model.PhotoFile, error) {
+	key := PhotoETag(photoRef, maxWidth, maxHeight)
+	if data, ok := p.disk.Get(key); ok {
+		return &model.PhotoFile{ContentType: http.DetectContentType(data), Data: data}, nil
+	}
+
+	val, err := p.group.DoContext(ctx, key, func(ctx context.Context) (interface{}, error) {
+		photo, err := p.GmapsInterface.GetPhoto(ctx, photoRef, maxWidth, maxHeight)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.disk.Set(key, photo.Data); err != nil {
+			p.log.Warnf("Gagal menyimpan cache foto: %v", err)
+		}
+		return photo, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return val.(*model.PhotoFile), nil
+}
+
+// Isi foto untuk photo_reference + ukuran yang sama tidak berubah, jadi ETag cukup dari input
+// dan bisa dicek sebelum foto diambil. Sekaligus dipakai sebagai nama file cache
+func PhotoETag(photoRef string, maxWidth, maxHeight int) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%d|%d", photoRef, maxWidth, maxHeight)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,70 @@
+package gmaps
+
+import (
+	"encoding/json"
+	"net/http"
+	"proyek1/internal/model"
+	"proyek1/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Status dari Places/Geocoding API (field "status" di body) ke error di utils
+func placesStatusError(log *logrus.Logger, api, status, message string) error {
+	var err error
+	switch status {
+	case "OK", "":
+		return nil
+	case "ZERO_RESULTS":
+		return utils.ErrGmapsZeroResults
+	case "NOT_FOUND":
+		err = utils.ErrGmapsNotFound
+	case "INVALID_REQUEST":
+		err = utils.ErrGmapsInvalidRequest
+	case "OVER_QUERY_LIMIT", "OVER_DAILY_LIMIT":
+		err = utils.ErrGmapsOverQueryLimit
+	case "REQUEST_DENIED":
+		err = utils.ErrGmapsRequestDenied
+	default:
+		err = utils.ErrGmapsUnknown
+	}
+	log.Warnf("Gmaps %s status %s: %s", api, status, message)
+	return err
+}
+
+// Status dari Routes API (format error Google Cloud, {"error": {"status": ...}})
+func routesStatusError(log *logrus.Logger, res *httpResponse) error {
+	var apiErr model.GmapsAPIError
+	_ = json.Unmarshal(res.Body, &apiErr)
+	log.Warnf("Gmaps routes status %d %s: %s", res.StatusCode, apiErr.Error.Status, apiErr.Error.Message)
+
+	switch apiErr.Error.Status {
+	case "INVALID_ARGUMENT", "FAILED_PRECONDITION", "OUT_OF_RANGE":
+		return utils.ErrGmapsInvalidRequest
+	case "NOT_FOUND":
+		return utils.ErrGmapsNotFound
+	case "RESOURCE_EXHAUSTED":
+		return utils.ErrGmapsOverQueryLimit
+	case "PERMISSION_DENIED", "UNAUTHENTICATED":
+		return utils.ErrGmapsRequestDenied
+	}
+	return httpStatusError(res.StatusCode)
+}
+
+// Untuk response tanpa status di body (contoh foto) atau status yang tidak dikenal
+func httpStatusError(code int) error {
+	switch {
+	case code == http.StatusOK:
+		return nil
+	case code == http.StatusNotFound:
+		return utils.ErrGmapsNotFound
+	case code == http.StatusBadRequest:
+		return utils.ErrGmapsInvalidRequest
+	case code == http.StatusTooManyRequests:
+		return utils.ErrGmapsOverQueryLimit
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return utils.ErrGmapsRequestDenied
+	default:
+		return utils.ErrGmapsUnknown
+	}
+}
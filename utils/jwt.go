@@ -9,27 +9,33 @@ import (
 )
 
 type JWT struct {
-	signKey string
+	signKey   string
+	accessTTL time.Duration
 }
 
 type JWTInterface interface {
-	GenerateToken(data *model.User) (string, error)
+	GenerateAccessToken(data *model.User, sessionID string) (string, error)
+	AccessTokenTTL() time.Duration
 	VerifyToken(tokenString string) (*model.User, error)
 }
 
-func NewJWT(s string) JWTInterface {
+func NewJWT(s string, accessTTL time.Duration) JWTInterface {
 	return &JWT{
-		signKey: s,
+		signKey:   s,
+		accessTTL: accessTTL,
 	}
 }
 
-func (j *JWT) GenerateToken(data *model.User) (string, error) {
+// Access token login, umurnya pendek dan terikat ke sesi (claim sid) supaya bisa dicabut lewat logout
+func (j *JWT) GenerateAccessToken(data *model.User, sessionID string) (string, error) {
+	now := time.Now()
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"id":    data.ID,
 		"email": data.Email,
 		"role":  data.Role,
-		"exp":   time.Now().Add(time.Hour * 24 * 30).Unix(),
-		"iat":   time.Now().Unix(), // tgl dibuat jwt ini
+		"sid":   sessionID,
+		"exp":   now.Add(j.accessTTL).Unix(),
+		"iat":   now.Unix(),
 	})
 
 	tokenString, err := token.SignedString([]byte(j.signKey))
@@ -40,10 +46,14 @@ func (j *JWT) GenerateToken(data *model.User) (string, error) {
 	return tokenString, nil
 }
 
+func (j *JWT) AccessTokenTTL() time.Duration {
+	return j.accessTTL
+}
+
 func (j *JWT) VerifyToken(tokenString string) (*model.User, error) {
 	tokenParse, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
 		return []byte(j.signKey), nil
-	})
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 	if err != nil {
 		return nil, fmt.Errorf(`terjadi kesalahan: %s`, err)
 	}
@@ -57,9 +67,11 @@ func (j *JWT) VerifyToken(tokenString string) (*model.User, error) {
 		return nil, fmt.Errorf("invalid claims")
 	}
 
+	sessionID, _ := claims["sid"].(string)
 	return &model.User{
-		ID:    claims["id"].(string),
-		Email: claims["email"].(string),
-		Role:  claims["role"].(string),
+		ID:        claims["id"].(string),
+		Email:     claims["email"].(string),
+		Role:      claims["role"].(string),
+		SessionID: sessionID,
 	}, nil
 }
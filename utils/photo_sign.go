@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type PhotoSigner struct {
+	secret  []byte
+	baseURL string
+	ttl     time.Duration
+}
+
+type PhotoSignerInterface interface {
+	SignPhoto(photoRef string, maxWidth, maxHeight int) string
+	VerifyPhoto(photoRef string, maxWidth, maxHeight int, exp, sig string) error
+}
+
+func NewPhotoSigner(secret, baseURL string, ttl time.Duration) PhotoSignerInterface {
+	return &PhotoSigner{
+		secret:  []byte(secret),
+		baseURL: baseURL,
+		ttl:     ttl,
+	}
+}
+
+// URL /photo yang sudah ditandatangani. Expiry dibulatkan ke atas per jam supaya URL
+// untuk foto yang sama tetap sama selama satu jam dan bisa di-cache browser
+func (p *PhotoSigner) SignPhoto(photoRef string, maxWidth, maxHeight int) string {
+	exp := time.Now().Add(p.ttl).Truncate(time.Hour).Add(time.Hour).Unix()
+
+	params := url.Values{}
+	params.Set("ref", photoRef)
+	if maxWidth > 0 {
+		params.Set("maxwidth", strconv.Itoa(maxWidth))
+	}
+	if maxHeight > 0 {
+		params.Set("maxheight", strconv.Itoa(maxHeight))
+	}
+	params.Set("exp", strconv.FormatInt(exp, 10))
+	params.Set("sig", p.signature(photoRef, maxWidth, maxHeight, exp))
+
+	return p.baseURL + "/photo?" + params.Encode()
+}
+
+func (p *PhotoSigner) VerifyPhoto(photoRef string, maxWidth, maxHeight int, exp, sig string) error {
+	if exp == "" || sig == "" {
+		return ErrPhotoSignature
+	}
+	expUnix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil {
+		return ErrPhotoSignature
+	}
+
+	expected := p.signature(photoRef, maxWidth, maxHeight, expUnix)
+	if !hmac.Equal([]byte(expected), []byte(sig)) {
+		return ErrPhotoSignature
+	}
+	if time.Now().Unix() > expUnix {
+		return ErrPhotoExpired
+	}
+	return nil
+}
+
+func (p *PhotoSigner) signature(photoRef string, maxWidth, maxHeight int, exp int64) string {
+	mac := hmac.New(sha256.New, p.secret)
+	fmt.Fprintf(mac, "%s|%d|%d|%d", photoRef, maxWidth, maxHeight, exp)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Pecah URL hasil SignPhoto jadi parameter yang dibaca ProxyPhotoHandler
+func parsePhotoURL(t *testing.T, signed string) (ref string, w, h int, exp, sig string) {
+	t.Helper()
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("URL tidak valid %q: %v", signed, err)
+	}
+	q := u.Query()
+	w, _ = strconv.Atoi(q.Get("maxwidth"))
+	h, _ = strconv.Atoi(q.Get("maxheight"))
+	return q.Get("ref"), w, h, q.Get("exp"), q.Get("sig")
+}
+
+func TestSignPhotoURL(t *testing.T) {
+	p := NewPhotoSigner("rahasia", "https://api.example.com", 24*time.Hour)
+	signed := p.SignPhoto("ref/abc+123", 400, 0)
+
+	if !strings.HasPrefix(signed, "https://api.example.com/photo?") {
+		t.Fatalf("URL = %q", signed)
+	}
+	ref, w, h, exp, sig := parsePhotoURL(t, signed)
+	if ref != "ref/abc+123" || w != 400 || h != 0 || sig == "" {
+		t.Errorf("parameter = %q %d %d %q", ref, w, h, sig)
+	}
+	if strings.Contains(signed, "maxheight") {
+		t.Errorf("maxheight 0 tidak boleh ikut di URL: %q", signed)
+	}
+
+	// expiry dibulatkan ke atas per jam, minimal sepanjang ttl
+	expUnix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil {
+		t.Fatalf("exp = %q", exp)
+	}
+	if expUnix%3600 != 0 {
+		t.Errorf("exp %d tidak dibulatkan per jam", expUnix)
+	}
+	minExp := time.Now().Add(24 * time.Hour).Unix()
+	if expUnix < minExp || expUnix > minExp+3600 {
+		t.Errorf("exp %d di luar [%d, %d]", expUnix, minExp, minExp+3600)
+	}
+	// kecuali kebetulan lewat pergantian jam di antara dua panggilan
+	if again := p.SignPhoto("ref/abc+123", 400, 0); again != signed && time.Now().Unix() < expUnix-24*3600 {
+		t.Errorf("URL untuk foto yang sama harus sama dalam satu jam:\n%s\n%s", signed, again)
+	}
+}
+
+func TestVerifyPhoto(t *testing.T) {
+	p := NewPhotoSigner("rahasia", "", time.Hour)
+	ref, w, h, exp, sig := parsePhotoURL(t, p.SignPhoto("ref-asli", 400, 300))
+
+	expiredSigner := NewPhotoSigner("rahasia", "", -3*time.Hour)
+	oldRef, oldW, oldH, oldExp, oldSig := parsePhotoURL(t, expiredSigner.SignPhoto("ref-asli", 400, 300))
+	later := strconv.FormatInt(time.Now().Add(48*time.Hour).Unix(), 10)
+
+	tests := []struct {
+		name   string
+		signer PhotoSignerInterface
+		ref    string
+		w, h   int
+		exp    string
+		sig    string
+		want   error
+	}{
+		{"valid", p, ref, w, h, exp, sig, nil},
+		{"w diubah", p, ref, 1600, h, exp, sig, ErrPhotoSignature},
+		{"h diubah", p, ref, w, 1600, exp, sig, ErrPhotoSignature},
+		{"ref diubah", p, "ref-lain", w, h, exp, sig, ErrPhotoSignature},
+		{"exp diperpanjang", p, ref, w, h, later, sig, ErrPhotoSignature},
+		{"exp bukan angka", p, ref, w, h, "besok", sig, ErrPhotoSignature},
+		{"tanpa sig", p, ref, w, h, exp, "", ErrPhotoSignature},
+		{"tanpa exp", p, ref, w, h, "", sig, ErrPhotoSignature},
+		{"secret lain", NewPhotoSigner("secret-lain", "", time.Hour), ref, w, h, exp, sig, ErrPhotoSignature},
+		{"expired", p, oldRef, oldW, oldH, oldExp, oldSig, ErrPhotoExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.signer.VerifyPhoto(tt.ref, tt.w, tt.h, tt.exp, tt.sig)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("VerifyPhoto = %v, mau %v", err, tt.want)
+			}
+		})
+	}
+}
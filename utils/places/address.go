@@ -0,0 +1,46 @@
+package places
+
+import "proyek1/internal/model"
+
+// Level administrasi Indonesia di Google: level_1 provinsi, level_2 kota/kabupaten,
+// level_3 kecamatan, level_4 kelurahan/desa
+func ParseAddress(components []model.AddressComponent) model.Address {
+	var res model.Address
+	for _, c := range components {
+		for _, t := range c.Types {
+			switch t {
+			case "administrative_area_level_1":
+				res.Province = c.LongName
+			case "administrative_area_level_2":
+				res.City = c.LongName
+			case "locality":
+				// dipakai kalau tidak ada level_2
+				if res.City == "" {
+					res.City = c.LongName
+				}
+			case "administrative_area_level_3":
+				res.District = c.LongName
+			case "administrative_area_level_4", "sublocality_level_1":
+				if res.Village == "" {
+					res.Village = c.LongName
+				}
+			case "postal_code":
+				res.PostalCode = c.LongName
+			case "country":
+				res.Country = c.LongName
+				res.CountryCode = c.ShortName
+			default:
+				continue
+			}
+			break
+		}
+	}
+	return res
+}
+
+func withAddress(results []model.GeocodeResult) []model.GeocodeResult {
+	for i := range results {
+		results[i].Address = ParseAddress(results[i].AddressComponents)
+	}
+	return results
+}
@@ -0,0 +1,43 @@
+package places
+
+import (
+	"context"
+	"proyek1/internal/model"
+	"proyek1/utils/gmaps"
+)
+
+type google struct {
+	gm gmaps.GmapsInterface
+}
+
+func NewGoogle(gm gmaps.GmapsInterface) Provider {
+	return &google{gm: gm}
+}
+
+func (g *google) Name() string {
+	return SourceGoogle
+}
+
+func (g *google) SearchObject(ctx context.Context, query string, opts model.SearchOptions) (model.Maps, error) {
+	return g.gm.GmapsSearchObject(ctx, query, opts)
+}
+
+func (g *google) SearchList(ctx context.Context, query string, opts model.SearchOptions) (model.MapsList, error) {
+	return g.gm.GmapsSearchList(ctx, query, opts)
+}
+
+func (g *google) Details(ctx context.Context, placeID, language string) (model.MapsGetByPlaceId, error) {
+	return g.gm.GmapsSearchByPlaceID(ctx, placeID, language)
+}
+
+func (g *google) Geocode(ctx context.Context, address string) ([]model.GeocodeResult, error) {
+	return g.gm.Geocode(ctx, address)
+}
+
+func (g *google) ReverseGeocode(ctx context.Context, lat, lng float64) ([]model.GeocodeResult, error) {
+	return g.gm.ReverseGeocode(ctx, lat, lng)
+}
+
+func (g *google) Autocomplete(ctx context.Context, input string, opts model.SearchOptions) ([]model.Prediction, error) {
+	return g.gm.Autocomplete(ctx, input, opts)
+}
@@ -0,0 +1,349 @@
+package places
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"proyek1/constant"
+	"proyek1/internal/model"
+	"proyek1/utils"
+	"proyek1/utils/geo"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kebijakan Nominatim publik: maksimal 1 request per detik dan wajib User-Agent
+const nominatimInterval = time.Second
+
+type nominatim struct {
+	baseURL string
+	email   string
+	client  *http.Client
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+func NewNominatim(baseURL, email string) Provider {
+	if baseURL == "" {
+		baseURL = constant.NominatimURL
+	}
+	return &nominatim{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		email:   email,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (n *nominatim) Name() string {
+	return SourceOSM
+}
+
+type nominatimPlace struct {
+	OsmType     string            `json:"osm_type"`
+	OsmID       int64             `json:"osm_id"`
+	Lat         string            `json:"lat"`
+	Lon         string            `json:"lon"`
+	Category    string            `json:"category"`
+	Type        string            `json:"type"`
+	Name        string            `json:"name"`
+	DisplayName string            `json:"display_name"`
+	Address     map[string]string `json:"address"`
+	ExtraTags   map[string]string `json:"extratags"`
+	Error       string            `json:"error"`
+}
+
+// Filter type dilakukan setelah hasil datang, jadi kalau ada type minta beberapa kandidat sekaligus
+func (n *nominatim) SearchObject(ctx context.Context, query string, opts model.SearchOptions) (model.Maps, error) {
+	limit := 1
+	if opts.Type != "" {
+		limit = 20
+	}
+	results, err := n.search(ctx, query, limit, opts)
+	if err != nil {
+		return model.Maps{}, err
+	}
+	if len(results) == 0 {
+		return model.Maps{}, utils.ErrGmapsZeroResults
+	}
+	return results[0], nil
+}
+
+// Nominatim tidak punya halaman berikutnya, semua hasil (maksimal 20) dikirim sekaligus
+func (n *nominatim) SearchList(ctx context.Context, query string, opts model.SearchOptions) (model.MapsList, error) {
+	if opts.PageToken != "" {
+		return model.MapsList{}, utils.ErrPageToken
+	}
+	results, err := n.search(ctx, query, 20, opts)
+	if err != nil {
+		return model.MapsList{}, err
+	}
+	if results == nil {
+		results = []model.Maps{}
+	}
+	return model.MapsList{Results: results}, nil
+}
+
+// Lokasi user jadi viewbox (bias, bukan batasan), region jadi countrycodes.
+// Type dicocokkan dengan kategori hasil konversi tag OSM
+func (n *nominatim) search(ctx context.Context, query string, limit int, opts model.SearchOptions) ([]model.Maps, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(limit))
+	setLanguage(params, opts.Language)
+	if opts.Location != nil {
+		radius := opts.Radius
+		if radius <= 0 {
+			radius = constant.SearchDefaultRadius
+		}
+		b := geo.BBoxAround(opts.Location.Latitude, opts.Location.Longitude, float64(radius))
+		params.Set("viewbox", fmt.Sprintf("%f,%f,%f,%f", b.MinLng, b.MaxLat, b.MaxLng, b.MinLat))
+	}
+	if opts.Region != "" {
+		params.Set("countrycodes", opts.Region)
+	}
+
+	var places []nominatimPlace
+	if err := n.get(ctx, "/search", params, &places); err != nil {
+		return nil, err
+	}
+
+	var results []model.Maps
+	for _, p := range places {
+		types := CategoriesFromTags(p.Category, p.Type, p.ExtraTags)
+		if opts.Type != "" && !containsType(types, opts.Type) {
+			continue
+		}
+		results = append(results, model.Maps{
+			PlaceID:          osmPlaceID(p.OsmType, p.OsmID),
+			Name:             placeName(p),
+			Geometry:         model.LocationResp{Lat: p.Lat, Lng: p.Lon},
+			FormattedAddress: p.DisplayName,
+			Types:            types,
+			Source:           SourceOSM,
+		})
+	}
+	return results, nil
+}
+
+func (n *nominatim) Details(ctx context.Context, placeID, language string) (model.MapsGetByPlaceId, error) {
+	osmID := strings.TrimPrefix(placeID, SourceOSM+":")
+	if osmID == placeID || osmID == "" {
+		return model.MapsGetByPlaceId{}, utils.ErrIDNotFound
+	}
+
+	params := url.Values{}
+	params.Set("osm_ids", osmID)
+	setLanguage(params, language)
+
+	var places []nominatimPlace
+	if err := n.get(ctx, "/lookup", params, &places); err != nil {
+		return model.MapsGetByPlaceId{}, err
+	}
+	if len(places) == 0 {
+		return model.MapsGetByPlaceId{}, utils.ErrIDNotFound
+	}
+
+	p := places[0]
+	lat, _ := strconv.ParseFloat(p.Lat, 64)
+	lng, _ := strconv.ParseFloat(p.Lon, 64)
+	return model.MapsGetByPlaceId{
+		PlaceID:          placeID,
+		Name:             placeName(p),
+		Geometry:         model.LocationResp{Lat: p.Lat, Lng: p.Lon},
+		FormattedAddress: p.DisplayName,
+		NavigasiURL:      fmt.Sprintf("https://www.google.com/maps/search/?api=1&query=%f,%f", lat, lng),
+		RegularOpeningHours: model.OpeningHour{
+			Periods: ParseOpeningHours(p.ExtraTags["opening_hours"]),
+		},
+		BusinessStatus:    businessStatus(p.ExtraTags),
+		Types:             CategoriesFromTags(p.Category, p.Type, p.ExtraTags),
+		AddressComponents: addressComponents(p),
+		Source:            SourceOSM,
+	}, nil
+}
+
+// Kebijakan Nominatim publik melarang autocomplete (request per ketikan)
+func (n *nominatim) Autocomplete(ctx context.Context, input string, opts model.SearchOptions) ([]model.Prediction, error) {
+	return nil, utils.ErrAutocomplete
+}
+
+func (n *nominatim) Geocode(ctx context.Context, address string) ([]model.GeocodeResult, error) {
+	params := url.Values{}
+	params.Set("q", address)
+	params.Set("limit", "5")
+
+	var places []nominatimPlace
+	if err := n.get(ctx, "/search", params, &places); err != nil {
+		return nil, err
+	}
+
+	var results []model.GeocodeResult
+	for _, p := range places {
+		results = append(results, geocodeResult(p))
+	}
+	return results, nil
+}
+
+func (n *nominatim) ReverseGeocode(ctx context.Context, lat, lng float64) ([]model.GeocodeResult, error) {
+	params := url.Values{}
+	params.Set("lat", strconv.FormatFloat(lat, 'f', -1, 64))
+	params.Set("lon", strconv.FormatFloat(lng, 'f', -1, 64))
+
+	var p nominatimPlace
+	if err := n.get(ctx, "/reverse", params, &p); err != nil {
+		return nil, err
+	}
+	if p.Error != "" {
+		return nil, nil
+	}
+	return []model.GeocodeResult{geocodeResult(p)}, nil
+}
+
+func (n *nominatim) get(ctx context.Context, path string, params url.Values, out interface{}) error {
+	if err := n.wait(ctx); err != nil {
+		return err
+	}
+
+	params.Set("format", "jsonv2")
+	params.Set("addressdetails", "1")
+	params.Set("extratags", "1")
+	if params.Get("accept-language") == "" {
+		params.Set("accept-language", constant.SearchDefaultLanguage)
+	}
+	if n.email != "" {
+		params.Set("email", n.email)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, n.baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("User-Agent", "nav-app/1.0")
+
+	response, err := n.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("nominatim status %d", response.StatusCode)
+	}
+	if err := json.NewDecoder(response.Body).Decode(out); err != nil {
+		return fmt.Errorf("error unmarshal nominatim: %w", err)
+	}
+	return nil
+}
+
+func setLanguage(params url.Values, language string) {
+	if language != "" {
+		params.Set("accept-language", language)
+	}
+}
+
+func containsType(types []string, t string) bool {
+	for _, v := range types {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Jaga jarak antar request sesuai batas Nominatim
+func (n *nominatim) wait(ctx context.Context) error {
+	n.mu.Lock()
+	next := n.last.Add(nominatimInterval)
+	now := time.Now()
+	if next.Before(now) {
+		next = now
+	}
+	n.last = next
+	n.mu.Unlock()
+
+	select {
+	case <-time.After(time.Until(next)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func osmPlaceID(osmType string, id int64) string {
+	if osmType == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%s%d", SourceOSM, strings.ToUpper(osmType[:1]), id)
+}
+
+func placeName(p nominatimPlace) string {
+	if p.Name != "" {
+		return p.Name
+	}
+	name, _, _ := strings.Cut(p.DisplayName, ",")
+	return name
+}
+
+func businessStatus(tags map[string]string) string {
+	if tags["disused"] == "yes" || tags["abandoned"] == "yes" {
+		return "CLOSED_PERMANENTLY"
+	}
+	return "OPERATIONAL"
+}
+
+// Komponen alamat OSM diberi type yang sama dengan Google supaya parsing alamat bisa dipakai bersama
+var osmAddressTypes = []struct {
+	key   string
+	types []string
+}{
+	{"road", []string{"route"}},
+	{"village", []string{"administrative_area_level_4", "political"}},
+	{"suburb", []string{"administrative_area_level_3", "political"}},
+	{"city_district", []string{"administrative_area_level_3", "political"}},
+	{"municipality", []string{"administrative_area_level_3", "political"}},
+	{"city", []string{"administrative_area_level_2", "locality", "political"}},
+	{"county", []string{"administrative_area_level_2", "political"}},
+	{"regency", []string{"administrative_area_level_2", "political"}},
+	{"state", []string{"administrative_area_level_1", "political"}},
+	{"postcode", []string{"postal_code"}},
+	{"country", []string{"country", "political"}},
+}
+
+func geocodeResult(p nominatimPlace) model.GeocodeResult {
+	res := model.GeocodeResult{
+		PlaceID:           osmPlaceID(p.OsmType, p.OsmID),
+		FormattedAddress:  p.DisplayName,
+		Geometry:          model.LocationResp{Lat: p.Lat, Lng: p.Lon},
+		Types:             []string{p.Category + ":" + p.Type},
+		AddressComponents: addressComponents(p),
+		Source:            SourceOSM,
+	}
+	return res
+}
+
+func addressComponents(p nominatimPlace) []model.AddressComponent {
+	var res []model.AddressComponent
+	used := map[string]bool{}
+	for _, a := range osmAddressTypes {
+		v, ok := p.Address[a.key]
+		if !ok || used[a.types[0]] {
+			continue
+		}
+		used[a.types[0]] = true
+		short := v
+		if a.key == "country" {
+			short = strings.ToUpper(p.Address["country_code"])
+		}
+		res = append(res, model.AddressComponent{
+			LongName:  v,
+			ShortName: short,
+			Types:     a.types,
+		})
+	}
+	return res
+}
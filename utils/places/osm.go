@@ -0,0 +1,169 @@
+package places
+
+import (
+	"errors"
+	"proyek1/internal/model"
+	"strings"
+)
+
+// Tag OSM (key=value) ke kode master_category yang dipakai Google
+var osmCategories = map[string][]string{
+	"tourism=attraction":       {"tourist_attraction"},
+	"tourism=viewpoint":        {"tourist_attraction"},
+	"tourism=artwork":          {"tourist_attraction"},
+	"tourism=museum":           {"museum", "tourist_attraction"},
+	"tourism=gallery":          {"art_gallery", "tourist_attraction"},
+	"tourism=theme_park":       {"amusement_park", "tourist_attraction"},
+	"tourism=zoo":              {"zoo", "tourist_attraction"},
+	"tourism=aquarium":         {"aquarium", "tourist_attraction"},
+	"tourism=camp_site":        {"campground"},
+	"tourism=hotel":            {"lodging"},
+	"tourism=guest_house":      {"lodging"},
+	"tourism=hostel":           {"lodging"},
+	"tourism=resort":           {"lodging"},
+	"leisure=park":             {"park"},
+	"leisure=garden":           {"park"},
+	"leisure=nature_reserve":   {"park", "natural_feature"},
+	"leisure=water_park":       {"amusement_park", "tourist_attraction"},
+	"leisure=beach_resort":     {"tourist_attraction"},
+	"natural=beach":            {"natural_feature", "tourist_attraction"},
+	"natural=peak":             {"natural_feature"},
+	"natural=volcano":          {"natural_feature", "tourist_attraction"},
+	"natural=cave_entrance":    {"natural_feature", "tourist_attraction"},
+	"waterway=waterfall":       {"natural_feature", "tourist_attraction"},
+	"amenity=place_of_worship": {"place_of_worship"},
+	"amenity=restaurant":       {"restaurant", "food"},
+	"amenity=cafe":             {"cafe", "food"},
+	"amenity=fast_food":        {"restaurant", "food"},
+	"shop=mall":                {"shopping_mall"},
+	"historic=*":               {"tourist_attraction"},
+	"tourism=*":                {"tourist_attraction"},
+}
+
+var osmReligion = map[string]string{
+	"muslim":    "mosque",
+	"christian": "church",
+	"hindu":     "hindu_temple",
+	"buddhist":  "hindu_temple",
+}
+
+func CategoriesFromTags(key, value string, tags map[string]string) []string {
+	cats, ok := osmCategories[key+"="+value]
+	if !ok {
+		cats = osmCategories[key+"=*"]
+	}
+
+	res := append([]string{}, cats...)
+	if key == "amenity" && value == "place_of_worship" {
+		if c, ok := osmReligion[tags["religion"]]; ok {
+			res = append(res, c)
+		}
+	}
+	if _, ok := tags["historic"]; ok && key != "historic" {
+		res = append(res, "tourist_attraction")
+	}
+	return dedupe(append(res, "point_of_interest"))
+}
+
+func dedupe(in []string) []string {
+	seen := map[string]bool{}
+	var res []string
+	for _, v := range in {
+		if !seen[v] {
+			seen[v] = true
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+var osmDays = map[string]int{"Su": 0, "Mo": 1, "Tu": 2, "We": 3, "Th": 4, "Fr": 5, "Sa": 6}
+
+var errOpeningHours = errors.New("format opening_hours tidak didukung")
+
+// Parse bentuk umum tag opening_hours, contoh "Mo-Fr 08:00-17:00; Sa 09:00-12:00" atau "24/7".
+// Aturan yang tidak dikenali (PH, sunrise, dsb) dilewati.
+func ParseOpeningHours(raw string) []model.Period {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+	if raw == "24/7" {
+		var periods []model.Period
+		for d := 0; d < 7; d++ {
+			periods = append(periods, period(d, "00:00", "23:59"))
+		}
+		return periods
+	}
+
+	var periods []model.Period
+	for _, rule := range strings.Split(raw, ";") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+
+		days := []int{0, 1, 2, 3, 4, 5, 6}
+		times := rule
+		if fields := strings.Fields(rule); len(fields) == 2 {
+			d, err := parseDays(fields[0])
+			if err != nil {
+				continue
+			}
+			days, times = d, fields[1]
+		} else if len(fields) != 1 {
+			continue
+		}
+		if times == "off" || times == "closed" {
+			continue
+		}
+
+		for _, span := range strings.Split(times, ",") {
+			open, close, ok := strings.Cut(span, "-")
+			if !ok || !validJam(open) || !validJam(close) {
+				continue
+			}
+			for _, d := range days {
+				periods = append(periods, period(d, open, close))
+			}
+		}
+	}
+	return periods
+}
+
+func parseDays(s string) ([]int, error) {
+	var days []int
+	for _, part := range strings.Split(s, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		start, ok := osmDays[from]
+		if !ok {
+			return nil, errOpeningHours
+		}
+		if !isRange {
+			days = append(days, start)
+			continue
+		}
+		end, ok := osmDays[to]
+		if !ok {
+			return nil, errOpeningHours
+		}
+		for d := start; ; d = (d + 1) % 7 {
+			days = append(days, d)
+			if d == end {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+func validJam(s string) bool {
+	return len(s) == 5 && s[2] == ':' && s[0] >= '0' && s[0] <= '2' && s[3] >= '0' && s[3] <= '5'
+}
+
+func period(day int, open, close string) model.Period {
+	return model.Period{
+		Open:  model.DayTime{Day: day, Time: open},
+		Close: model.DayTime{Day: day, Time: close},
+	}
+}
@@ -0,0 +1,107 @@
+package places
+
+import (
+	"context"
+	"proyek1/config"
+	"proyek1/internal/model"
+	"proyek1/utils/gmaps"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	SourceGoogle = gmaps.Source
+	SourceOSM    = "osm"
+)
+
+type Searcher interface {
+	SearchObject(ctx context.Context, query string, opts model.SearchOptions) (model.Maps, error)
+	SearchList(ctx context.Context, query string, opts model.SearchOptions) (model.MapsList, error)
+}
+
+// language kosong berarti bahasa default (constant.SearchDefaultLanguage)
+type DetailFetcher interface {
+	Details(ctx context.Context, placeID, language string) (model.MapsGetByPlaceId, error)
+}
+
+type Geocoder interface {
+	Geocode(ctx context.Context, address string) ([]model.GeocodeResult, error)
+	ReverseGeocode(ctx context.Context, lat, lng float64) ([]model.GeocodeResult, error)
+}
+
+type Autocompleter interface {
+	Autocomplete(ctx context.Context, input string, opts model.SearchOptions) ([]model.Prediction, error)
+}
+
+type Provider interface {
+	Name() string
+	Searcher
+	DetailFetcher
+	Geocoder
+	Autocompleter
+}
+
+// ID tempat dari OSM diberi prefix "osm:" supaya tidak bentrok dengan place_id Google
+func SourceOf(placeID string) string {
+	if strings.HasPrefix(placeID, SourceOSM+":") {
+		return SourceOSM
+	}
+	return SourceGoogle
+}
+
+// Search dan geocode memakai PLACES_PROVIDER, detail diarahkan sesuai sumber ID tempat,
+// autocomplete selalu ke google. Alamat hasil geocode dan detail diurai di sini untuk semua provider
+func NewProvider(c config.PLACES, gm gmaps.GmapsInterface, log *logrus.Logger) Provider {
+	r := &router{
+		providers: map[string]Provider{
+			SourceGoogle: NewGoogle(gm),
+			SourceOSM:    NewNominatim(c.NOMINATIM_URL, c.NOMINATIM_EMAIL),
+		},
+	}
+
+	r.def = r.providers[SourceGoogle]
+	if p, ok := r.providers[strings.ToLower(c.PLACES_PROVIDER)]; ok {
+		r.def = p
+	} else if c.PLACES_PROVIDER != "" {
+		log.Warnf("places provider %q tidak dikenal, pakai google", c.PLACES_PROVIDER)
+	}
+	return r
+}
+
+type router struct {
+	def       Provider
+	providers map[string]Provider
+}
+
+func (r *router) Name() string {
+	return r.def.Name()
+}
+
+func (r *router) SearchObject(ctx context.Context, query string, opts model.SearchOptions) (model.Maps, error) {
+	return r.def.SearchObject(ctx, query, opts)
+}
+
+func (r *router) SearchList(ctx context.Context, query string, opts model.SearchOptions) (model.MapsList, error) {
+	return r.def.SearchList(ctx, query, opts)
+}
+
+func (r *router) Details(ctx context.Context, placeID, language string) (model.MapsGetByPlaceId, error) {
+	result, err := r.providers[SourceOf(placeID)].Details(ctx, placeID, language)
+	result.Address = ParseAddress(result.AddressComponents)
+	return result, err
+}
+
+func (r *router) Autocomplete(ctx context.Context, input string, opts model.SearchOptions) ([]model.Prediction, error) {
+	return r.providers[SourceGoogle].Autocomplete(ctx, input, opts)
+}
+
+func (r *router) Geocode(ctx context.Context, address string) ([]model.GeocodeResult, error) {
+	results, err := r.def.Geocode(ctx, address)
+	return withAddress(results), err
+}
+
+func (r *router) ReverseGeocode(ctx context.Context, lat, lng float64) ([]model.GeocodeResult, error) {
+	results, err := r.def.ReverseGeocode(ctx, lat, lng)
+	return withAddress(results), err
+}
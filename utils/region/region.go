@@ -0,0 +1,122 @@
+package region
+
+import (
+	"regexp"
+	"strings"
+)
+
+type Province struct {
+	Code    string   // kode wilayah Kemendagri
+	Name    string   // nama baku
+	Aliases []string // nama lain yang dipakai Google/OSM (termasuk bahasa Inggris)
+}
+
+// Provinsi di Indonesia (38), urut kode
+var Provinces = []Province{
+	{"11", "Aceh", []string{"Nanggroe Aceh Darussalam", "Daerah Istimewa Aceh"}},
+	{"12", "Sumatera Utara", []string{"North Sumatra", "Sumatra Utara"}},
+	{"13", "Sumatera Barat", []string{"West Sumatra", "Sumatra Barat"}},
+	{"14", "Riau", nil},
+	{"15", "Jambi", nil},
+	{"16", "Sumatera Selatan", []string{"South Sumatra", "Sumatra Selatan"}},
+	{"17", "Bengkulu", nil},
+	{"18", "Lampung", nil},
+	{"19", "Kepulauan Bangka Belitung", []string{"Bangka Belitung", "Bangka Belitung Islands", "Babel"}},
+	{"21", "Kepulauan Riau", []string{"Riau Islands", "Kepri"}},
+	{"31", "DKI Jakarta", []string{"Daerah Khusus Ibukota Jakarta", "Daerah Khusus Jakarta", "Jakarta", "Special Capital Region of Jakarta"}},
+	{"32", "Jawa Barat", []string{"West Java", "Jabar"}},
+	{"33", "Jawa Tengah", []string{"Central Java", "Jateng"}},
+	{"34", "DI Yogyakarta", []string{"Daerah Istimewa Yogyakarta", "Special Region of Yogyakarta", "Yogyakarta", "DIY"}},
+	{"35", "Jawa Timur", []string{"East Java", "Jatim"}},
+	{"36", "Banten", nil},
+	{"51", "Bali", nil},
+	{"52", "Nusa Tenggara Barat", []string{"West Nusa Tenggara", "NTB"}},
+	{"53", "Nusa Tenggara Timur", []string{"East Nusa Tenggara", "NTT"}},
+	{"61", "Kalimantan Barat", []string{"West Kalimantan", "Kalbar"}},
+	{"62", "Kalimantan Tengah", []string{"Central Kalimantan", "Kalteng"}},
+	{"63", "Kalimantan Selatan", []string{"South Kalimantan", "Kalsel"}},
+	{"64", "Kalimantan Timur", []string{"East Kalimantan", "Kaltim"}},
+	{"65", "Kalimantan Utara", []string{"North Kalimantan", "Kaltara"}},
+	{"71", "Sulawesi Utara", []string{"North Sulawesi", "Sulut"}},
+	{"72", "Sulawesi Tengah", []string{"Central Sulawesi", "Sulteng"}},
+	{"73", "Sulawesi Selatan", []string{"South Sulawesi", "Sulsel"}},
+	{"74", "Sulawesi Tenggara", []string{"Southeast Sulawesi", "Sultra"}},
+	{"75", "Gorontalo", nil},
+	{"76", "Sulawesi Barat", []string{"West Sulawesi", "Sulbar"}},
+	{"81", "Maluku", nil},
+	{"82", "Maluku Utara", []string{"North Maluku", "Malut"}},
+	{"91", "Papua", nil},
+	{"92", "Papua Barat", []string{"West Papua"}},
+	{"93", "Papua Selatan", []string{"South Papua"}},
+	{"94", "Papua Tengah", []string{"Central Papua"}},
+	{"95", "Papua Pegunungan", []string{"Highland Papua"}},
+	{"96", "Papua Barat Daya", []string{"Southwest Papua"}},
+}
+
+var provinceIndex = func() map[string]Province {
+	idx := make(map[string]Province)
+	for _, p := range Provinces {
+		idx[p.Code] = p
+		idx[normalize(p.Name)] = p
+		for _, a := range p.Aliases {
+			idx[normalize(a)] = p
+		}
+	}
+	return idx
+}()
+
+// Cari provinsi dari kode atau nama (baku, alias, dengan/tanpa kata "Provinsi")
+func FindProvince(nameOrCode string) (Province, bool) {
+	key := normalize(nameOrCode)
+	for _, prefix := range []string{"provinsi ", "province of ", "prov "} {
+		key = strings.TrimPrefix(key, prefix)
+	}
+	key = strings.TrimSuffix(key, " province")
+	p, ok := provinceIndex[key]
+	return p, ok
+}
+
+// Nama kabupaten/kota tanpa penanda "Kabupaten"/"Kota"/"Regency"/"City", dipakai sama persis
+// waktu tempat disimpan dan waktu filter ?regency=. Google kadang mengirim "Sleman", kadang
+// "Kabupaten Sleman", jadi penanda dibuang supaya keduanya jatuh ke wilayah yang sama.
+// Akibatnya kabupaten dan kota dengan nama sama (contoh Bogor) dihitung jadi satu
+func NormalizeRegency(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	lower := strings.ToLower(name)
+
+	for _, prefix := range []string{"kabupaten ", "kab. ", "kab ", "kota administrasi ", "kota adm. ", "kota "} {
+		if strings.HasPrefix(lower, prefix) {
+			return strings.TrimSpace(name[len(prefix):])
+		}
+	}
+	for _, suffix := range []string{" regency", " city"} {
+		if strings.HasSuffix(lower, suffix) {
+			return strings.TrimSpace(name[:len(name)-len(suffix)])
+		}
+	}
+	return name
+}
+
+// Nama kecamatan tanpa penanda "Kecamatan"/"District", sama seperti NormalizeRegency
+func NormalizeDistrict(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	lower := strings.ToLower(name)
+
+	for _, prefix := range []string{"kecamatan ", "kec. ", "kec "} {
+		if strings.HasPrefix(lower, prefix) {
+			return strings.TrimSpace(name[len(prefix):])
+		}
+	}
+	for _, suffix := range []string{" subdistrict", " sub-district", " district"} {
+		if strings.HasSuffix(lower, suffix) {
+			return strings.TrimSpace(name[:len(name)-len(suffix)])
+		}
+	}
+	return name
+}
+
+var nonAlnum = regexp.MustCompile(`[^a-z0-9]+`)
+
+func normalize(s string) string {
+	return strings.TrimSpace(nonAlnum.ReplaceAllString(strings.ToLower(s), " "))
+}
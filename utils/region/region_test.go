@@ -0,0 +1,63 @@
+package region
+
+import "testing"
+
+// Nama dari data tersimpan dan dari filter harus jatuh ke nilai yang sama
+func TestNormalizeRegency(t *testing.T) {
+	tests := map[string]string{
+		"Kabupaten Sleman":                "Sleman",
+		"Kab. Sleman":                     "Sleman",
+		"kab  sleman":                     "sleman",
+		"Sleman Regency":                  "Sleman",
+		"Sleman":                          "Sleman",
+		"Kota Yogyakarta":                 "Yogyakarta",
+		"Yogyakarta City":                 "Yogyakarta",
+		"Kota Administrasi Jakarta Pusat": "Jakarta Pusat",
+		"Kota Adm. Jakarta Selatan":       "Jakarta Selatan",
+		"Kotamobagu":                      "Kotamobagu",
+		"Kotabaru":                        "Kotabaru",
+		"":                                "",
+	}
+	for in, want := range tests {
+		if got := NormalizeRegency(in); got != want {
+			t.Errorf("NormalizeRegency(%q) = %q, mau %q", in, got, want)
+		}
+	}
+}
+
+func TestNormalizeDistrict(t *testing.T) {
+	tests := map[string]string{
+		"Kecamatan Depok":       "Depok",
+		"Kec. Depok":            "Depok",
+		"Depok District":        "Depok",
+		"Gambir Subdistrict":    "Gambir",
+		"Gambir Sub-district":   "Gambir",
+		"Depok":                 "Depok",
+		"  Kecamatan   Gambir ": "Gambir",
+	}
+	for in, want := range tests {
+		if got := NormalizeDistrict(in); got != want {
+			t.Errorf("NormalizeDistrict(%q) = %q, mau %q", in, got, want)
+		}
+	}
+}
+
+func TestFindProvince(t *testing.T) {
+	tests := map[string]string{
+		"32":                         "32",
+		"Jawa Barat":                 "32",
+		"West Java":                  "32",
+		"Provinsi Jawa Barat":        "32",
+		"DKI Jakarta":                "31",
+		"Daerah Istimewa Yogyakarta": "34",
+	}
+	for in, want := range tests {
+		p, ok := FindProvince(in)
+		if !ok || p.Code != want {
+			t.Errorf("FindProvince(%q) = %q, %v, mau %q", in, p.Code, ok, want)
+		}
+	}
+	if _, ok := FindProvince("Atlantis"); ok {
+		t.Error("provinsi tidak dikenal harus false")
+	}
+}
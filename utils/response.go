@@ -1,7 +1,10 @@
 package utils
 
+import "proyek1/constant"
+
 type Response struct {
 	Status  string      `json:"status"`
+	Code    string      `json:"code,omitempty"` // hanya untuk error yang punya kode, lihat ErrorCode
 	Message string      `json:"message"`
 	Data    interface{} `json:"data"`
 }
@@ -22,6 +25,15 @@ func ResponseHandler(status, message string, data interface{}) Response {
 	return response
 }
 
+// Response gagal dari error, sudah termasuk kode error kalau ada
+func ErrorResponseHandler(err error) Response {
+	return Response{
+		Status:  constant.StatusFail,
+		Code:    ErrorCode(err),
+		Message: err.Error(),
+	}
+}
+
 func MetadataFormatResponse(status string, message string, metadata interface{}, data interface{}) MetadataResponse {
 	response := MetadataResponse{
 		Status:   status,
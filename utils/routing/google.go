@@ -0,0 +1,23 @@
+package routing
+
+import (
+	"context"
+	"proyek1/internal/model"
+	"proyek1/utils/gmaps"
+)
+
+type google struct {
+	gm gmaps.GmapsInterface
+}
+
+func NewGoogle(gm gmaps.GmapsInterface) RoutingProvider {
+	return &google{gm: gm}
+}
+
+func (g *google) Name() string {
+	return ProviderGoogle
+}
+
+func (g *google) Route(ctx context.Context, req model.RequestRouteMaps) (*model.ResponseRouteMaps, error) {
+	return g.gm.RouteToDestination(ctx, req)
+}
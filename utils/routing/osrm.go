@@ -0,0 +1,236 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"proyek1/constant"
+	"proyek1/internal/model"
+	"proyek1/utils/gmaps"
+	"strings"
+	"time"
+)
+
+var (
+	errNoRoute         = errors.New("rute tidak ditemukan")
+	errModeUnsupported = errors.New("travel mode tidak didukung provider ini")
+)
+
+// Profile bawaan osrm-backend (car.lua, bike.lua, foot.lua)
+var osrmProfile = map[string]string{
+	constant.TravelDrive:      "car",
+	constant.TravelTwoWheeler: "car",
+	constant.TravelWalk:       "foot",
+	constant.TravelBicycle:    "bike",
+}
+
+// Client HTTP untuk server yang kompatibel dengan OSRM route service (/route/v1)
+type osrm struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewOSRM(baseURL string) RoutingProvider {
+	return &osrm{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (o *osrm) Name() string {
+	return ProviderOSRM
+}
+
+type osrmResponse struct {
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Routes  []osrmRoute `json:"routes"`
+}
+
+type osrmRoute struct {
+	Distance float64 `json:"distance"`
+	Duration float64 `json:"duration"`
+	Geometry string  `json:"geometry"`
+	Legs     []struct {
+		Distance float64 `json:"distance"`
+		Duration float64 `json:"duration"`
+		Steps    []struct {
+			Distance float64 `json:"distance"`
+			Duration float64 `json:"duration"`
+			Geometry string  `json:"geometry"`
+			Name     string  `json:"name"`
+			Maneuver struct {
+				Type     string    `json:"type"`
+				Modifier string    `json:"modifier"`
+				Location []float64 `json:"location"`
+			} `json:"maneuver"`
+		} `json:"steps"`
+	} `json:"legs"`
+}
+
+func (o *osrm) Route(ctx context.Context, req model.RequestRouteMaps) (*model.ResponseRouteMaps, error) {
+	profile, ok := osrmProfile[req.TravelMode]
+	if !ok {
+		return nil, errModeUnsupported
+	}
+
+	origin := req.Origin.Location.LatLng
+	dest := req.Destination.Location.LatLng
+	query := url.Values{}
+	query.Set("overview", "full")
+	query.Set("geometries", "polyline")
+	query.Set("steps", "true")
+	query.Set("alternatives", fmt.Sprintf("%t", req.ComputeAlternativeRoutes))
+	requestURL := fmt.Sprintf("%s/route/v1/%s/%f,%f;%f,%f?%s", o.baseURL, profile,
+		origin.Longitude, origin.Latitude, dest.Longitude, dest.Latitude, query.Encode())
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := o.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	var body osrmResponse
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("error unmarshal osrm: %w", err)
+	}
+	if body.Code != "Ok" {
+		return nil, fmt.Errorf("osrm %s: %s", body.Code, body.Message)
+	}
+
+	res := &model.ResponseRouteMaps{}
+	for _, r := range body.Routes {
+		route := model.Route{
+			Distance: int(r.Distance),
+			Duration: osrmDuration(r.Duration),
+			Polyline: model.Polyline{EncodePolyline: r.Geometry},
+		}
+		for _, l := range r.Legs {
+			leg := model.RouteLeg{
+				Distance: int(l.Distance),
+				Duration: osrmDuration(l.Duration),
+			}
+			for _, s := range l.Steps {
+				leg.Steps = append(leg.Steps, model.RouteStep{
+					Distance:   int(s.Distance),
+					Duration:   osrmDuration(s.Duration),
+					Polyline:   model.Polyline{EncodePolyline: s.Geometry},
+					TravelMode: req.TravelMode,
+					NavigationInstruction: model.NavigationInstruction{
+						Maneuver:     osrmManeuver(s.Maneuver.Type, s.Maneuver.Modifier),
+						Instructions: osrmInstruction(s.Maneuver.Type, s.Maneuver.Modifier, s.Name),
+					},
+				})
+			}
+			if n := len(leg.Steps); n > 0 {
+				leg.StartLocation = stepLocation(l.Steps[0].Maneuver.Location)
+				leg.EndLocation = stepLocation(l.Steps[n-1].Maneuver.Location)
+			}
+			route.Legs = append(route.Legs, leg)
+		}
+		res.Routes = append(res.Routes, route)
+	}
+
+	if err := gmaps.ParseRoutes(res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func osrmDuration(seconds float64) string {
+	return fmt.Sprintf("%ds", int(seconds+0.5))
+}
+
+func stepLocation(lngLat []float64) model.LocationReq {
+	if len(lngLat) < 2 {
+		return model.LocationReq{}
+	}
+	return model.LocationReq{LatLng: model.LatLng{Latitude: lngLat[1], Longitude: lngLat[0]}}
+}
+
+// Samakan maneuver OSRM dengan enum maneuver Routes API
+func osrmManeuver(kind, modifier string) string {
+	switch kind {
+	case "depart":
+		return "DEPART"
+	case "arrive":
+		return ""
+	case "merge":
+		return "MERGE"
+	case "new name":
+		return "NAME_CHANGE"
+	case "roundabout", "rotary", "roundabout turn", "exit roundabout", "exit rotary":
+		if strings.Contains(modifier, "left") {
+			return "ROUNDABOUT_LEFT"
+		}
+		return "ROUNDABOUT_RIGHT"
+	case "on ramp", "off ramp":
+		if strings.Contains(modifier, "left") {
+			return "RAMP_LEFT"
+		}
+		return "RAMP_RIGHT"
+	case "fork":
+		if strings.Contains(modifier, "left") {
+			return "FORK_LEFT"
+		}
+		return "FORK_RIGHT"
+	}
+
+	switch modifier {
+	case "uturn":
+		return "UTURN_LEFT"
+	case "sharp right":
+		return "TURN_SHARP_RIGHT"
+	case "right":
+		return "TURN_RIGHT"
+	case "slight right":
+		return "TURN_SLIGHT_RIGHT"
+	case "sharp left":
+		return "TURN_SHARP_LEFT"
+	case "left":
+		return "TURN_LEFT"
+	case "slight left":
+		return "TURN_SLIGHT_LEFT"
+	default:
+		return "STRAIGHT"
+	}
+}
+
+var osrmArah = map[string]string{
+	"uturn":        "Putar balik",
+	"sharp right":  "Belok tajam ke kanan",
+	"right":        "Belok kanan",
+	"slight right": "Sedikit ke kanan",
+	"straight":     "Lurus",
+	"slight left":  "Sedikit ke kiri",
+	"left":         "Belok kiri",
+	"sharp left":   "Belok tajam ke kiri",
+}
+
+func osrmInstruction(kind, modifier, name string) string {
+	var text string
+	switch kind {
+	case "depart":
+		text = "Mulai perjalanan"
+	case "arrive":
+		return "Tiba di tujuan"
+	case "roundabout", "rotary":
+		text = "Masuk bundaran"
+	default:
+		var ok bool
+		if text, ok = osrmArah[modifier]; !ok {
+			text = "Lanjutkan"
+		}
+	}
+	if name != "" {
+		text += " ke " + name
+	}
+	return text
+}
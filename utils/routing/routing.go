@@ -0,0 +1,87 @@
+package routing
+
+import (
+	"context"
+	"proyek1/config"
+	"proyek1/internal/model"
+	"proyek1/utils/gmaps"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ProviderGoogle   = "google"
+	ProviderOSRM     = "osrm"
+	ProviderStraight = "straight"
+)
+
+type RoutingProvider interface {
+	Name() string
+	Route(ctx context.Context, req model.RequestRouteMaps) (*model.ResponseRouteMaps, error)
+}
+
+// Urutan provider dari ROUTING_PROVIDER, contoh "google,osrm,straight".
+// Kalau satu gagal otomatis lanjut ke provider berikutnya.
+func NewProvider(c config.ROUTING, gm gmaps.GmapsInterface, log *logrus.Logger) RoutingProvider {
+	names := strings.Split(c.ROUTING_PROVIDER, ",")
+	if c.ROUTING_PROVIDER == "" {
+		names = []string{ProviderGoogle, ProviderStraight}
+	}
+
+	var providers []RoutingProvider
+	for _, n := range names {
+		switch strings.ToLower(strings.TrimSpace(n)) {
+		case ProviderGoogle:
+			providers = append(providers, NewGoogle(gm))
+		case ProviderOSRM:
+			if c.OSRM_URL == "" {
+				log.Warn("routing osrm dilewati, OSRM_URL kosong")
+				continue
+			}
+			providers = append(providers, NewOSRM(c.OSRM_URL))
+		case ProviderStraight:
+			providers = append(providers, NewStraightLine())
+		default:
+			log.Warnf("routing provider %q tidak dikenal", n)
+		}
+	}
+	if len(providers) == 0 {
+		providers = append(providers, NewStraightLine())
+	}
+
+	return &fallback{providers: providers, log: log}
+}
+
+type fallback struct {
+	providers []RoutingProvider
+	log       *logrus.Logger
+}
+
+func (f *fallback) Name() string {
+	var names []string
+	for _, p := range f.providers {
+		names = append(names, p.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+func (f *fallback) Route(ctx context.Context, req model.RequestRouteMaps) (*model.ResponseRouteMaps, error) {
+	var lastErr error
+	for _, p := range f.providers {
+		res, err := p.Route(ctx, req)
+		if err == nil && len(res.Routes) > 0 {
+			res.Provider = p.Name()
+			return res, nil
+		}
+		if err == nil {
+			err = errNoRoute
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		f.log.WithContext(ctx).Warnf("routing %s gagal, coba provider berikutnya: %v", p.Name(), err)
+	}
+	return nil, lastErr
+}
@@ -0,0 +1,83 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"proyek1/constant"
+	"proyek1/internal/model"
+	"proyek1/utils/geo"
+	"proyek1/utils/gmaps"
+)
+
+// Kecepatan rata-rata (km/jam) dan faktor belokan jalan terhadap garis lurus
+var straightProfile = map[string]struct {
+	speed  float64
+	detour float64
+}{
+	constant.TravelDrive:      {speed: 40, detour: 1.4},
+	constant.TravelTwoWheeler: {speed: 35, detour: 1.3},
+	constant.TravelWalk:       {speed: 4.5, detour: 1.25},
+	constant.TravelBicycle:    {speed: 14, detour: 1.3},
+	constant.TravelTransit:    {speed: 25, detour: 1.5},
+}
+
+// Perkiraan tanpa layanan luar, dipakai kalau Google dan OSRM tidak bisa dihubungi
+type straightLine struct{}
+
+func NewStraightLine() RoutingProvider {
+	return &straightLine{}
+}
+
+func (s *straightLine) Name() string {
+	return ProviderStraight
+}
+
+func (s *straightLine) Route(ctx context.Context, req model.RequestRouteMaps) (*model.ResponseRouteMaps, error) {
+	origin := req.Origin.Location.LatLng
+	dest := req.Destination.Location.LatLng
+
+	profile, ok := straightProfile[req.TravelMode]
+	if !ok {
+		profile = straightProfile[constant.TravelDrive]
+	}
+
+	distance := int(math.Round(geo.Haversine(origin.Latitude, origin.Longitude, dest.Latitude, dest.Longitude) * profile.detour))
+	seconds := int(math.Round(float64(distance) / 1000 / profile.speed * 3600))
+	duration := fmt.Sprintf("%ds", seconds)
+	polyline := model.Polyline{
+		EncodePolyline: geo.EncodePolyline([]geo.Point{
+			{Lat: origin.Latitude, Lng: origin.Longitude},
+			{Lat: dest.Latitude, Lng: dest.Longitude},
+		}),
+	}
+
+	res := &model.ResponseRouteMaps{
+		Routes: []model.Route{{
+			Distance: distance,
+			Duration: duration,
+			Polyline: polyline,
+			Legs: []model.RouteLeg{{
+				Distance:      distance,
+				Duration:      duration,
+				StartLocation: req.Origin.Location,
+				EndLocation:   req.Destination.Location,
+				Steps: []model.RouteStep{{
+					Distance:   distance,
+					Duration:   duration,
+					Polyline:   polyline,
+					TravelMode: req.TravelMode,
+					NavigationInstruction: model.NavigationInstruction{
+						Maneuver:     "DEPART",
+						Instructions: "Perkiraan garis lurus menuju tujuan, rute jalan tidak tersedia",
+					},
+				}},
+			}},
+		}},
+	}
+
+	if err := gmaps.ParseRoutes(res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}